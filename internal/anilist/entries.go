@@ -5,7 +5,7 @@ import (
 	"errors"
 	"github.com/rs/zerolog"
 	"github.com/samber/lo"
-	"github.com/seanime-app/seanime-server/internal/limiter"
+	"seanime/internal/limiter"
 )
 
 func (c *Client) AddMediaToPlanning(mIds []int, rateLimiter *limiter.Limiter, logger *zerolog.Logger) error {
@@ -0,0 +1,102 @@
+package anidb
+
+import (
+	"fmt"
+	"github.com/goccy/go-json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// cacheEntry wraps a FileInfo with the time it was cached, so we can tell complete entries
+// (cached forever, per AniDB's rules for finished files) apart from stale Incomplete ones,
+// which are worth refreshing on the next lookup.
+type cacheEntry struct {
+	CachedAt time.Time `json:"cachedAt"`
+	Info     *FileInfo `json:"info"`
+}
+
+// incompleteEntryTTL bounds how long an Incomplete entry is trusted before a re-lookup is
+// attempted. Complete entries never expire - AniDB file records are immutable once final.
+const incompleteEntryTTL = 24 * time.Hour
+
+// Cache is a small on-disk cache for FileInfo, keyed by (ed2k, size) and mirrored by FID so a
+// lookup by either key is O(1).
+type Cache struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewCache creates a Cache rooted at dir, creating it if necessary.
+func NewCache(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Cache{dir: dir}, nil
+}
+
+// GetByEd2k returns the cached FileInfo for the given eD2k hash + size, if present and fresh.
+func (c *Cache) GetByEd2k(ed2kHash string, size int64) (*FileInfo, bool) {
+	return c.get(c.ed2kKey(ed2kHash, size))
+}
+
+// GetByFID returns the cached FileInfo for the given AniDB file ID, if present and fresh.
+func (c *Cache) GetByFID(fid int) (*FileInfo, bool) {
+	return c.get(c.fidKey(fid))
+}
+
+// Set stores fi under both its (ed2k, size) key and its FID key.
+func (c *Cache) Set(ed2kHash string, size int64, fi *FileInfo) error {
+	entry := cacheEntry{CachedAt: time.Now(), Info: fi}
+
+	if err := c.write(c.ed2kKey(ed2kHash, size), entry); err != nil {
+		return err
+	}
+	if fi.FID != 0 {
+		if err := c.write(c.fidKey(fi.FID), entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Cache) get(key string) (*FileInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	raw, err := os.ReadFile(filepath.Join(c.dir, key+".json"))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, false
+	}
+
+	if entry.Info != nil && entry.Info.Incomplete && time.Since(entry.CachedAt) > incompleteEntryTTL {
+		return nil, false
+	}
+
+	return entry.Info, true
+}
+
+func (c *Cache) write(key string, entry cacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(c.dir, key+".json"), content, 0666)
+}
+
+func (c *Cache) ed2kKey(ed2kHash string, size int64) string {
+	return fmt.Sprintf("ed2k_%s_%d", ed2kHash, size)
+}
+
+func (c *Cache) fidKey(fid int) string {
+	return fmt.Sprintf("fid_%d", fid)
+}
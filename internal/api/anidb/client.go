@@ -0,0 +1,260 @@
+// Package anidb implements a minimal client for AniDB's UDP API, used to resolve exact
+// file-level metadata (AID/EID/GID) from an eD2k hash + file size.
+//
+// This is intentionally narrow in scope: it only implements what's needed to AUTH, run a
+// FILE lookup, and LOGOUT. It is not a general-purpose AniDB client.
+package anidb
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	defaultServerAddr = "api.anidb.net:9000"
+	clientName        = "seanime"
+	clientVersion     = 1
+	protocolVersion   = 3
+
+	// AniDB's flood policy: at most 1 packet every 2s, and a mandatory 4s cooldown after any
+	// burst of 5 packets within a 10s window. throttle enforces both: a flat 2s spacing, plus
+	// the cooldown once burstSize packets fall within the rolling burstWindow.
+	minRequestInterval = 2 * time.Second
+	burstCooldown      = 4 * time.Second
+	burstSize          = 5
+	burstWindow        = 10 * time.Second
+)
+
+// ErrBanned is returned when AniDB responds with code 555 BANNED. Callers MUST NOT retry
+// automatically when they see this error - a retry loop against a ban is how accounts get
+// permanently blacklisted.
+var ErrBanned = errors.New("anidb: client is banned (555)")
+
+var (
+	ErrNotLoggedIn  = errors.New("anidb: not logged in")
+	ErrLoginFailed  = errors.New("anidb: login failed")
+	ErrNoSuchFile   = errors.New("anidb: no such file (320)")
+	ErrUnknownReply = errors.New("anidb: unknown reply")
+)
+
+// Credentials holds the AniDB account used to authenticate UDP API requests.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// Client is a minimal, single-session AniDB UDP API client.
+//
+// It is safe for concurrent use; requests are serialized internally to respect AniDB's flood
+// control policy.
+type Client struct {
+	creds      Credentials
+	serverAddr string
+	logger     *zerolog.Logger
+
+	mu           sync.Mutex
+	conn         *net.UDPConn
+	sessionKey   string
+	lastRequests []time.Time // timestamps of the last few requests, for burst tracking
+}
+
+// NewClient creates a new AniDB UDP client. It does not connect or authenticate until Auth is
+// called.
+func NewClient(creds Credentials, logger *zerolog.Logger) *Client {
+	return &Client{
+		creds:      creds,
+		serverAddr: defaultServerAddr,
+		logger:     logger,
+	}
+}
+
+// Auth connects to the AniDB UDP API and authenticates, storing the resulting session key.
+func (c *Client) Auth() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		addr, err := net.ResolveUDPAddr("udp", c.serverAddr)
+		if err != nil {
+			return err
+		}
+		conn, err := net.DialUDP("udp", nil, addr)
+		if err != nil {
+			return err
+		}
+		c.conn = conn
+	}
+
+	cmd := fmt.Sprintf("AUTH user=%s&pass=%s&protover=%d&client=%s&clientver=%d&enc=UTF8",
+		c.creds.Username, c.creds.Password, protocolVersion, clientName, clientVersion)
+
+	reply, err := c.send(cmd)
+	if err != nil {
+		return err
+	}
+
+	code, rest := splitReplyCode(reply)
+	switch code {
+	case "200", "201":
+		// reply is "200 <session key> LOGIN ACCEPTED"
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			return ErrLoginFailed
+		}
+		c.sessionKey = fields[0]
+		return nil
+	case "500":
+		return ErrLoginFailed
+	case "555":
+		return ErrBanned
+	default:
+		return fmt.Errorf("%w: AUTH returned %s", ErrLoginFailed, code)
+	}
+}
+
+// LoggedIn reports whether the client currently holds a session key.
+func (c *Client) LoggedIn() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sessionKey != ""
+}
+
+// Logout ends the AniDB session. It is a no-op if the client isn't logged in.
+func (c *Client) Logout() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sessionKey == "" {
+		return nil
+	}
+
+	_, err := c.sendLocked(fmt.Sprintf("LOGOUT s=%s", c.sessionKey))
+	c.sessionKey = ""
+	if c.conn != nil {
+		_ = c.conn.Close()
+		c.conn = nil
+	}
+	return err
+}
+
+// FileByEd2k looks up exact file metadata by eD2k hash + size via AniDB's FILE command.
+func (c *Client) FileByEd2k(ed2kHash string, size int64) (*FileInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sessionKey == "" {
+		return nil, ErrNotLoggedIn
+	}
+
+	fmask, amask := fileMasks()
+	cmd := fmt.Sprintf("FILE size=%d&ed2k=%s&s=%s&fmask=%s&amask=%s", size, ed2kHash, c.sessionKey, fmask, amask)
+
+	reply, err := c.sendLocked(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	code, rest := splitReplyCode(reply)
+	switch code {
+	case "220":
+		return parseFileReply(rest)
+	case "320":
+		return nil, ErrNoSuchFile
+	case "501", "506":
+		// Not logged in / invalid session - drop the session key and the connection, so the next
+		// Auth call reconnects from scratch. FILE is not retried here; the caller gets
+		// ErrNotLoggedIn and has to Auth again before repeating the lookup.
+		c.sessionKey = ""
+		if c.conn != nil {
+			_ = c.conn.Close()
+			c.conn = nil
+		}
+		return nil, ErrNotLoggedIn
+	case "555":
+		return nil, ErrBanned
+	default:
+		return nil, fmt.Errorf("%w: FILE returned %s", ErrUnknownReply, code)
+	}
+}
+
+// send sends cmd, enforcing the flood-control policy. It does not take c.mu itself; callers
+// must already hold it, same as for sendLocked.
+func (c *Client) send(cmd string) (string, error) {
+	return c.sendLocked(cmd)
+}
+
+// sendLocked sends cmd over the UDP connection. Callers must hold c.mu.
+func (c *Client) sendLocked(cmd string) (string, error) {
+	if c.conn == nil {
+		return "", errors.New("anidb: not connected")
+	}
+
+	c.throttle()
+
+	if _, err := c.conn.Write([]byte(cmd)); err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, 4096)
+	_ = c.conn.SetReadDeadline(time.Now().Add(15 * time.Second))
+	n, err := c.conn.Read(buf)
+	if err != nil {
+		return "", err
+	}
+
+	return string(buf[:n]), nil
+}
+
+// throttle blocks until it is safe to send another packet, per AniDB's flood policy:
+// at most 1 packet / 2s, with an extra cooldown after a burst of 5 packets within 10s.
+// Callers must hold c.mu.
+func (c *Client) throttle() {
+	now := time.Now()
+
+	if len(c.lastRequests) > 0 {
+		if since := now.Sub(c.lastRequests[len(c.lastRequests)-1]); since < minRequestInterval {
+			time.Sleep(minRequestInterval - since)
+			now = time.Now()
+		}
+	}
+
+	// Drop timestamps outside the burst window
+	cutoff := now.Add(-burstWindow)
+	recent := c.lastRequests[:0]
+	for _, t := range c.lastRequests {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	c.lastRequests = recent
+
+	if len(c.lastRequests) >= burstSize {
+		time.Sleep(burstCooldown)
+		now = time.Now()
+	}
+
+	c.lastRequests = append(c.lastRequests, now)
+}
+
+func splitReplyCode(reply string) (code string, rest string) {
+	reply = strings.TrimRight(reply, "\r\n")
+	parts := strings.SplitN(reply, " ", 2)
+	code = parts[0]
+	if len(parts) > 1 {
+		rest = parts[1]
+	}
+	return
+}
+
+func fileMasks() (fmask, amask string) {
+	// Fixed masks requesting: aid, eid, gid, group name, file state (deprecated/crc ok), and
+	// amask for quality/source/dub/sub languages. These are raw hex bitmasks per the AniDB
+	// UDP API spec (AnimeInfo/FileInfo sections).
+	return "7800000000", "00008200"
+}
@@ -0,0 +1,91 @@
+package anidb
+
+import "strings"
+
+// FileInfo is the subset of AniDB's FILE command response we care about for identifying a
+// local video file.
+type FileInfo struct {
+	FID int
+	AID int
+	EID int
+	GID int
+	// GroupName is the release group's short name (e.g. "SubsPlease"), empty if the file is
+	// not associated with a group.
+	GroupName string
+	// Deprecated is true if this file entry has been superseded by a newer one on AniDB.
+	Deprecated bool
+	// CRCOK is true if the file's CRC matches AniDB's records. It is false both when the CRC is
+	// known-bad and when AniDB has no CRC information for this file.
+	CRCOK   bool
+	Source  string
+	Quality string
+	// DubLanguages/SubLanguages are AniDB's language lists for this file, which the reply
+	// separates with apostrophes (').
+	DubLanguages []string
+	SubLanguages []string
+	// Incomplete is true when the reply didn't carry every field we asked for (fmask/amask
+	// mismatch, or a partial/old file entry). Callers should treat such entries cautiously.
+	Incomplete bool
+}
+
+// parseFileReply parses the data line(s) of a 220 FILE reply into a FileInfo.
+//
+// The reply body is a single pipe-delimited line whose field order matches the fmask/amask we
+// sent in fileMasks(). Since AniDB requires masks to be provided exactly as hex bitmasks and
+// parsing every possible combination is out of scope here, this only extracts the fixed set of
+// fields we always request.
+func parseFileReply(body string) (*FileInfo, error) {
+	body = strings.TrimSpace(body)
+	lines := strings.SplitN(body, "\n", 2)
+	if len(lines) == 0 || lines[0] == "" {
+		return nil, ErrUnknownReply
+	}
+
+	fields := strings.Split(lines[0], "|")
+
+	fi := &FileInfo{}
+	get := func(i int) string {
+		if i < len(fields) {
+			return fields[i]
+		}
+		fi.Incomplete = true
+		return ""
+	}
+
+	fi.FID = atoiSafe(get(0))
+	fi.AID = atoiSafe(get(1))
+	fi.EID = atoiSafe(get(2))
+	fi.GID = atoiSafe(get(3))
+	fi.GroupName = get(4)
+	fi.Deprecated = get(5) == "1"
+	fi.CRCOK = get(6) == "1"
+	fi.Source = get(7)
+	fi.Quality = get(8)
+	if dub := get(9); dub != "" {
+		fi.DubLanguages = strings.Split(dub, "'")
+	}
+	if sub := get(10); sub != "" {
+		fi.SubLanguages = strings.Split(sub, "'")
+	}
+
+	return fi, nil
+}
+
+func atoiSafe(s string) int {
+	n := 0
+	neg := false
+	for i, r := range s {
+		if i == 0 && r == '-' {
+			neg = true
+			continue
+		}
+		if r < '0' || r > '9' {
+			return 0
+		}
+		n = n*10 + int(r-'0')
+	}
+	if neg {
+		n = -n
+	}
+	return n
+}
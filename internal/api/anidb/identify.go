@@ -0,0 +1,33 @@
+package anidb
+
+// IdentifyByEd2k resolves file metadata from one or both eD2k hash variants of a file. It
+// checks the cache first, then falls back to a live FILE lookup against AniDB, trying redHash
+// and then blueHash (the two disagree only for files whose size is an exact multiple of the
+// eD2k chunk size, so in practice only one round trip is ever needed).
+//
+// Results are written back to cache under whichever hash actually matched.
+func IdentifyByEd2k(client *Client, cache *Cache, redHash, blueHash string, size int64) (*FileInfo, error) {
+	if fi, ok := cache.GetByEd2k(redHash, size); ok {
+		return fi, nil
+	}
+	if blueHash != redHash {
+		if fi, ok := cache.GetByEd2k(blueHash, size); ok {
+			return fi, nil
+		}
+	}
+
+	fi, err := client.FileByEd2k(redHash, size)
+	if err == ErrNoSuchFile && blueHash != redHash {
+		fi, err = client.FileByEd2k(blueHash, size)
+		if err == nil {
+			_ = cache.Set(blueHash, size, fi)
+		}
+		return fi, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_ = cache.Set(redHash, size, fi)
+	return fi, nil
+}
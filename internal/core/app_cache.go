@@ -0,0 +1,36 @@
+package core
+
+// EnableHTTPCache turns on the shared on-disk HTTP response cache used by the manga providers
+// and the image downloader. Useful to flip back on after debugging scraper regressions.
+func (a *App) EnableHTTPCache() {
+	if a.httpCache == nil {
+		return
+	}
+	a.httpCache.EnableCache()
+}
+
+// DisableHTTPCache turns off the shared on-disk HTTP response cache, forcing every provider
+// request to hit the network. This does not clear entries already on disk.
+func (a *App) DisableHTTPCache() {
+	if a.httpCache == nil {
+		return
+	}
+	a.httpCache.DisableCache()
+}
+
+// IsHTTPCacheEnabled reports whether the shared HTTP response cache is currently active.
+func (a *App) IsHTTPCacheEnabled() bool {
+	if a.httpCache == nil {
+		return false
+	}
+	return a.httpCache.IsCacheEnabled()
+}
+
+// PurgeHTTPCache deletes every cached response whose URL starts with prefix, or everything
+// when prefix is empty.
+func (a *App) PurgeHTTPCache(prefix string) error {
+	if a.httpCache == nil {
+		return nil
+	}
+	return a.httpCache.Purge(prefix)
+}
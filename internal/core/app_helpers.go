@@ -4,8 +4,8 @@ import (
 	"context"
 	"errors"
 	"github.com/samber/lo"
-	"github.com/seanime-app/seanime/internal/api/anilist"
-	"github.com/seanime-app/seanime/internal/database/models"
+	"seanime/internal/api/anilist"
+	"seanime/internal/database/models"
 )
 
 func (a *App) IsOffline() bool {
@@ -82,6 +82,11 @@ func (a *App) RefreshAnilistCollection() (*anilist.AnimeCollection, error) {
 	// Save the collection to TorrentstreamRepository
 	a.TorrentstreamRepository.SetAnimeCollection(collection)
 
+	// Precompute recommendations for the most recently watched titles, so the media entry
+	// screen never has to wait on a cold AniList/MAL fetch
+	const recommendationPrecomputeCount = 10
+	a.Recommendations.PrecomputeForRecentlyWatched(collection, recommendationPrecomputeCount)
+
 	return collection, nil
 }
 
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"seanime/internal/database/models"
+)
+
+func (db *Database) GetDownloadQueueItems() ([]*models.DownloadQueueItem, error) {
+	var res []*models.DownloadQueueItem
+	err := db.gormdb.Order("position asc").Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetUnfinishedDownloadQueueItems returns every item that was interrupted (queued, downloading
+// or paused) when the app last stopped, so they can be automatically resumed on startup.
+func (db *Database) GetUnfinishedDownloadQueueItems() ([]*models.DownloadQueueItem, error) {
+	var res []*models.DownloadQueueItem
+	err := db.gormdb.
+		Where("state IN ?", []models.DownloadQueueItemState{
+			models.DownloadQueueItemStateQueued,
+			models.DownloadQueueItemStateDownloading,
+			models.DownloadQueueItemStatePaused,
+		}).
+		Order("position asc").
+		Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (db *Database) InsertDownloadQueueItem(item *models.DownloadQueueItem) error {
+	return db.gormdb.Create(item).Error
+}
+
+func (db *Database) UpdateDownloadQueueItem(item *models.DownloadQueueItem) error {
+	return db.gormdb.Save(item).Error
+}
+
+func (db *Database) DeleteDownloadQueueItem(id uint) error {
+	return db.gormdb.Delete(&models.DownloadQueueItem{}, id).Error
+}
+
+func (db *Database) UpdateDownloadQueueItemPositions(orderedIds []uint) error {
+	for position, id := range orderedIds {
+		if err := db.gormdb.Model(&models.DownloadQueueItem{}).Where("id = ?", id).Update("position", position).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// BaseModel is embedded by every row-backed model in this package, providing the standard gorm
+// primary key and timestamps.
+type BaseModel struct {
+	ID        uint      `gorm:"column:id;primaryKey" json:"id"`
+	CreatedAt time.Time `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"column:updated_at" json:"updatedAt"`
+	// SchemaVersion is bumped whenever the shape of the owning row changes in a way that requires
+	// migrating forward. Settings revisions carry the version of the row they were snapshotted
+	// from so a restore can tell whether it predates a schema change.
+	SchemaVersion int `gorm:"column:schema_version" json:"schemaVersion"`
+	// RevisionID is a column for the ID of a SettingsRevision. The settings handlers do not set
+	// it when they write the row (they replace BaseModel wholesale), so it stays 0 there.
+	RevisionID int `gorm:"column:revision_id" json:"revisionId"`
+}
@@ -0,0 +1,24 @@
+package models
+
+// DownloadQueueItemState is the lifecycle state of a DownloadQueueItem.
+type DownloadQueueItemState string
+
+const (
+	DownloadQueueItemStateQueued      DownloadQueueItemState = "queued"
+	DownloadQueueItemStateDownloading DownloadQueueItemState = "downloading"
+	DownloadQueueItemStatePaused      DownloadQueueItemState = "paused"
+	DownloadQueueItemStateFailed      DownloadQueueItemState = "failed"
+	DownloadQueueItemStateDone        DownloadQueueItemState = "done"
+)
+
+// DownloadQueueItem is a single manga chapter queued for download.
+// Items are processed one at a time by the chapter_downloader queue, in Position order.
+type DownloadQueueItem struct {
+	BaseModel
+	Provider  string                 `gorm:"column:provider" json:"provider"`
+	MediaId   int                    `gorm:"column:media_id" json:"mediaId"`
+	ChapterId string                 `gorm:"column:chapter_id" json:"chapterId"`
+	State     DownloadQueueItemState `gorm:"column:state" json:"state"`
+	Position  int                    `gorm:"column:position" json:"position"`
+	Retries   int                    `gorm:"column:retries" json:"retries"`
+}
@@ -0,0 +1,15 @@
+package models
+
+// SettingsRevision is a snapshot of the settings row taken right before it gets overwritten, so a
+// save that leaves the app in a broken state (a module that fails to initialize against the new
+// settings) can be rolled back. Only the latest few revisions are kept - see
+// Database.pruneSettingsRevisions.
+type SettingsRevision struct {
+	BaseModel
+	// SchemaVersion is copied from the settings row this revision was taken from, so a restore
+	// can tell whether the snapshot predates a schema change that needs migrating forward.
+	SchemaVersion int `gorm:"column:schema_version" json:"schemaVersion"`
+	// Data is the settings row serialized to JSON at snapshot time. It's kept off the list
+	// endpoint's response (see handlers.HandleGetSettingsRevisions) and only decoded on restore.
+	Data string `gorm:"column:data" json:"-"`
+}
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"github.com/goccy/go-json"
+	"seanime/internal/database/models"
+)
+
+// maxSettingsRevisions bounds how many settings snapshots are kept - enough to undo a few bad
+// saves in a row without letting the table grow unbounded.
+const maxSettingsRevisions = 20
+
+// CreateSettingsRevision snapshots settings as a new SettingsRevision row, then prunes anything
+// past maxSettingsRevisions. Call this with the *previous* settings, before writing the new ones.
+func (db *Database) CreateSettingsRevision(settings *models.Settings) (*models.SettingsRevision, error) {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := &models.SettingsRevision{
+		SchemaVersion: settings.SchemaVersion,
+		Data:          string(data),
+	}
+	if err := db.gormdb.Create(revision).Error; err != nil {
+		return nil, err
+	}
+
+	// Best-effort: a failed prune just means the table grows a bit - it shouldn't fail the save.
+	_ = db.pruneSettingsRevisions()
+
+	return revision, nil
+}
+
+// GetSettingsRevisions returns the kept revisions, most recent first.
+func (db *Database) GetSettingsRevisions() ([]*models.SettingsRevision, error) {
+	var revisions []*models.SettingsRevision
+	if err := db.gormdb.Order("id desc").Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// GetSettingsRevision returns a single revision by ID.
+func (db *Database) GetSettingsRevision(id uint) (*models.SettingsRevision, error) {
+	var revision models.SettingsRevision
+	if err := db.gormdb.First(&revision, id).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// RestoreSettingsRevision decodes revision id's snapshot and upserts it back as the live settings
+// row, returning the restored settings.
+func (db *Database) RestoreSettingsRevision(id uint) (*models.Settings, error) {
+	revision, err := db.GetSettingsRevision(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings models.Settings
+	if err := json.Unmarshal([]byte(revision.Data), &settings); err != nil {
+		return nil, err
+	}
+
+	return db.UpsertSettings(&settings)
+}
+
+// pruneSettingsRevisions deletes every revision past maxSettingsRevisions, oldest first.
+func (db *Database) pruneSettingsRevisions() error {
+	var count int64
+	if err := db.gormdb.Model(&models.SettingsRevision{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count <= maxSettingsRevisions {
+		return nil
+	}
+
+	var stale []models.SettingsRevision
+	if err := db.gormdb.
+		Order("id asc").
+		Limit(int(count - maxSettingsRevisions)).
+		Find(&stale).Error; err != nil {
+		return err
+	}
+
+	for _, revision := range stale {
+		if err := db.gormdb.Delete(&models.SettingsRevision{}, revision.ID).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,7 +1,7 @@
 package db
 
 import (
-	"github.com/seanime-app/seanime/internal/models"
+	"seanime/internal/models"
 )
 
 func (db *Database) GetAutoDownloaderItems() ([]*models.AutoDownloaderItem, error) {
@@ -34,6 +34,25 @@ func (db *Database) GetAutoDownloaderItemByMediaId(mId int) ([]*models.AutoDownl
 	return res, nil
 }
 
+// GetMatchingAutoDownloaderItemsByMediaId is the same as GetAutoDownloaderItemByMediaId, but
+// only returns items whose EpisodeSelector matches episodeNumber. isLatestEpisode should be true
+// when episodeNumber is the most recently released episode, to support the "latest" selector.
+func (db *Database) GetMatchingAutoDownloaderItemsByMediaId(mId int, episodeNumber int, isLatestEpisode bool) ([]*models.AutoDownloaderItem, error) {
+	items, err := db.GetAutoDownloaderItemByMediaId(mId)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*models.AutoDownloaderItem, 0, len(items))
+	for _, item := range items {
+		if MatchesEpisodeSelector(item, episodeNumber, isLatestEpisode) {
+			ret = append(ret, item)
+		}
+	}
+
+	return ret, nil
+}
+
 func (db *Database) InsertAutoDownloaderItem(item *models.AutoDownloaderItem) error {
 	err := db.gormdb.Create(item).Error
 	if err != nil {
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"errors"
+	"seanime/internal/models"
+	"strconv"
+	"strings"
+)
+
+type (
+	// episodeSelector is the parsed form of models.AutoDownloaderItem.EpisodeSelector.
+	episodeSelector struct {
+		// latest matches only the most recently released episode.
+		latest bool
+		// from/to bound a closed range, e.g. "12-34" -> from=12, to=34.
+		// to == -1 means an open range, e.g. "50-" -> from=50, to=-1.
+		from int
+		to   int
+	}
+)
+
+// ErrInvalidEpisodeSelector is returned when an EpisodeSelector string cannot be parsed.
+var ErrInvalidEpisodeSelector = errors.New("invalid episode selector")
+
+// parseEpisodeSelector parses a models.AutoDownloaderItem.EpisodeSelector string.
+// Supported formats: "12" (single), "12-34" (range), "50-" (open range), "latest".
+func parseEpisodeSelector(raw string) (*episodeSelector, error) {
+	raw = strings.TrimSpace(raw)
+
+	if raw == "" {
+		return nil, nil // No selector, matches everything
+	}
+
+	if strings.EqualFold(raw, "latest") {
+		return &episodeSelector{latest: true}, nil
+	}
+
+	if !strings.Contains(raw, "-") {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, ErrInvalidEpisodeSelector
+		}
+		return &episodeSelector{from: n, to: n}, nil
+	}
+
+	parts := strings.SplitN(raw, "-", 2)
+	from, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return nil, ErrInvalidEpisodeSelector
+	}
+
+	if strings.TrimSpace(parts[1]) == "" {
+		return &episodeSelector{from: from, to: -1}, nil
+	}
+
+	to, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return nil, ErrInvalidEpisodeSelector
+	}
+
+	return &episodeSelector{from: from, to: to}, nil
+}
+
+// matches reports whether episodeNumber satisfies the selector. isLatestEpisode is only
+// relevant to the "latest" selector.
+func (s *episodeSelector) matches(episodeNumber int, isLatestEpisode bool) bool {
+	if s == nil {
+		return true
+	}
+	if s.latest {
+		return isLatestEpisode
+	}
+	if s.to == -1 {
+		return episodeNumber >= s.from
+	}
+	return episodeNumber >= s.from && episodeNumber <= s.to
+}
+
+// MatchesEpisodeSelector reports whether episodeNumber is selected by item's EpisodeSelector.
+// An empty/unset EpisodeSelector matches every episode, preserving the pre-existing behavior.
+func MatchesEpisodeSelector(item *models.AutoDownloaderItem, episodeNumber int, isLatestEpisode bool) bool {
+	selector, err := parseEpisodeSelector(item.EpisodeSelector)
+	if err != nil {
+		// An invalid selector should not silently match everything
+		return false
+	}
+	return selector.matches(episodeNumber, isLatestEpisode)
+}
@@ -0,0 +1,53 @@
+package entities
+
+import "time"
+
+// LocalFileType classifies what role a LocalFile plays in an episode's release.
+type LocalFileType string
+
+const (
+	LocalFileTypeMain    LocalFileType = "main"
+	LocalFileTypeSpecial LocalFileType = "special"
+	LocalFileTypeNC      LocalFileType = "nc"
+)
+
+// LocalFileMetadata holds the episode data parsed out of a LocalFile's path.
+type LocalFileMetadata struct {
+	Type    LocalFileType `json:"type"`
+	Episode int           `json:"episode"`
+}
+
+// LocalFile represents a single media file discovered on disk during a scan.
+type LocalFile struct {
+	Path             string            `json:"path"`
+	Size             int64             `json:"size"`
+	ModTime          time.Time         `json:"modTime"`
+	ParsedData       interface{}       `json:"parsedData"`
+	ParsedFolderData interface{}       `json:"parsedFolderData"`
+	Metadata         LocalFileMetadata `json:"metadata"`
+	Locked           bool              `json:"locked"`
+	Ignored          bool              `json:"ignored"`
+	// Fingerprint is a cached content hash (see scanner.fingerprintFile), used by incremental
+	// scans to detect a file that moved/renamed since the last scan without re-hashing it.
+	Fingerprint string `json:"fingerprint"`
+}
+
+// IsLocked returns whether the user has locked this file against automatic changes.
+func (lf *LocalFile) IsLocked() bool {
+	return lf.Locked
+}
+
+// IsIgnored returns whether the user has excluded this file from matching/hydration.
+func (lf *LocalFile) IsIgnored() bool {
+	return lf.Ignored
+}
+
+// IsIncluded returns whether lfs already contains an entry for this file's path.
+func (lf *LocalFile) IsIncluded(lfs []*LocalFile) bool {
+	for _, other := range lfs {
+		if other.Path == lf.Path {
+			return true
+		}
+	}
+	return false
+}
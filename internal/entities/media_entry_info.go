@@ -3,9 +3,10 @@ package entities
 import (
 	"errors"
 	"github.com/samber/lo"
-	"github.com/seanime-app/seanime-server/internal/anilist"
-	"github.com/seanime-app/seanime-server/internal/anizip"
 	"github.com/sourcegraph/conc/pool"
+	"seanime/internal/anilist"
+	"seanime/internal/anizip"
+	"seanime/internal/torrent/quality"
 	"strconv"
 )
 
@@ -23,6 +24,13 @@ type (
 		EpisodeNumber int                `json:"episodeNumber"`
 		AniDBEpisode  string             `json:"aniDBEpisode"`
 		Episode       *MediaEntryEpisode `json:"episode"`
+		// ReleaseQuality is the classified source of the selected torrent candidate for this
+		// episode (BluRay, WEB-DL, WEBRip, HDTV, DVD, CAM). Empty until the torrent-selection
+		// pipeline has picked a candidate for this episode.
+		ReleaseQuality quality.ReleaseQuality `json:"releaseQuality"`
+		// QualityScore is the numeric score backing ReleaseQuality, used to compare candidates
+		// of the same source (e.g. 1080p BluRay vs 720p BluRay).
+		QualityScore float64 `json:"qualityScore"`
 	}
 
 	NewMediaEntryInfoOptions struct {
@@ -31,9 +39,27 @@ type (
 		anizipMedia  *anizip.Media
 		media        *anilist.BaseMedia
 		anilistEntry *anilist.AnimeCollection_MediaListCollection_Lists_Entries
+		// MinQuality drops torrent candidates worse than this quality. Leave as
+		// quality.ReleaseQualityUnknown to disable the floor.
+		MinQuality quality.ReleaseQuality
+		// ExcludeCamRips hard-drops candidates classified as CAM/TS/TELESYNC/WORKPRINT releases.
+		ExcludeCamRips bool
+		// QualityPreference is the user's ordered, most-preferred-first list of qualities, used
+		// to rank candidates that both meet MinQuality.
+		QualityPreference []quality.ReleaseQuality
 	}
 )
 
+// QualityFilterOptions builds the quality.FilterOptions the torrent-selection pipeline should
+// use when pruning and ranking candidates for this media entry.
+func (opts *NewMediaEntryInfoOptions) QualityFilterOptions() quality.FilterOptions {
+	return quality.FilterOptions{
+		ExcludeCamRips: opts.ExcludeCamRips,
+		MinQuality:     opts.MinQuality,
+		Preferred:      opts.QualityPreference,
+	}
+}
+
 // NewMediaEntryInfo creates a new MediaEntryInfo
 func NewMediaEntryInfo(opts *NewMediaEntryInfoOptions) (*MediaEntryInfo, error) {
 
@@ -162,6 +188,14 @@ func NewMediaEntryInfo(opts *NewMediaEntryInfoOptions) (*MediaEntryInfo, error)
 				progressOffset:       0,
 				isDownloaded:         false,
 			})
+
+			// ReleaseQuality/QualityScore are left unset here - toDownloadSlice (above) already
+			// excludes episodes with an existing LocalFileTypeMain file, so there are no on-disk
+			// candidates to classify for an episode reached by this loop. They're populated once
+			// the torrent-selection pipeline (AutoDownloader, which searches and ranks actual
+			// torrent candidates via QualityFilterOptions/quality.FilterAndRank before handing one
+			// to the download client) picks a candidate for this episode.
+
 			return str
 		})
 	}
@@ -0,0 +1,109 @@
+package browsercookie
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// loadChromiumCookies reads cookies out of a Chromium-based browser's "Cookies" database,
+// decrypting values via whatever platform-specific keychain holds the browser's encryption key.
+func loadChromiumCookies(src Source, hosts []string) ([]*http.Cookie, error) {
+	dbPath := src.Path
+	if dbPath == "" {
+		profileDir, err := chromiumProfileDir(src.Profile)
+		if err != nil {
+			return nil, err
+		}
+		dbPath = filepath.Join(profileDir, "Cookies")
+	}
+
+	tmpPath, err := copyToTemp(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmpPath)
+
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?mode=ro&immutable=1", tmpPath))
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT host_key, name, encrypted_value, path, is_secure, is_httponly, expires_utc FROM cookies`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cookies := make([]*http.Cookie, 0)
+	for rows.Next() {
+		var host, name, path string
+		var encryptedValue []byte
+		var isSecure, isHttpOnly int
+		var expiresUtc int64
+		if err := rows.Scan(&host, &name, &encryptedValue, &path, &isSecure, &isHttpOnly, &expiresUtc); err != nil {
+			continue
+		}
+		if !matchesHost(host, hosts) {
+			continue
+		}
+
+		value, err := decryptChromiumValue(encryptedValue)
+		if err != nil {
+			// Skip cookies we can't decrypt rather than failing the whole import - a stale
+			// key or unsupported platform shouldn't block every other cookie.
+			continue
+		}
+
+		cookies = append(cookies, &http.Cookie{
+			Name:     name,
+			Value:    string(value),
+			Domain:   host,
+			Path:     path,
+			Secure:   isSecure == 1,
+			HttpOnly: isHttpOnly == 1,
+			Expires:  chromiumEpochToTime(expiresUtc),
+		})
+	}
+
+	return cookies, rows.Err()
+}
+
+// chromiumEpochToTime converts Chromium's "microseconds since 1601-01-01" timestamp format.
+func chromiumEpochToTime(chromiumUs int64) time.Time {
+	if chromiumUs == 0 {
+		return time.Time{}
+	}
+	const chromiumEpochOffsetUs = 11644473600 * 1_000_000
+	unixUs := chromiumUs - chromiumEpochOffsetUs
+	return time.UnixMicro(unixUs)
+}
+
+func chromiumProfileDir(profile string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	var root string
+	switch runtime.GOOS {
+	case "windows":
+		root = filepath.Join(os.Getenv("LOCALAPPDATA"), "Google", "Chrome", "User Data")
+	case "darwin":
+		root = filepath.Join(home, "Library", "Application Support", "Google", "Chrome")
+	default:
+		root = filepath.Join(home, ".config", "google-chrome")
+	}
+
+	if profile == "" {
+		profile = "Default"
+	}
+	return filepath.Join(root, profile), nil
+}
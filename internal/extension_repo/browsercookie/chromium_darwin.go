@@ -0,0 +1,69 @@
+//go:build darwin
+
+package browsercookie
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha1"
+	"errors"
+	"golang.org/x/crypto/pbkdf2"
+	"os/exec"
+	"strings"
+)
+
+var chromiumSaltDarwin = []byte("saltysalt")
+
+// decryptChromiumValue decrypts a Chromium-on-macOS cookie value. Chrome derives an AES-128-CBC
+// key from the "Chrome Safe Storage" password stored in the macOS Keychain via PBKDF2.
+func decryptChromiumValue(encrypted []byte) ([]byte, error) {
+	if !bytes.HasPrefix(encrypted, []byte("v10")) {
+		return nil, errors.New("browsercookie: unrecognized cookie encryption version")
+	}
+	ciphertext := encrypted[3:]
+
+	password, err := chromiumSafeStoragePassword()
+	if err != nil {
+		return nil, err
+	}
+
+	key := pbkdf2.Key([]byte(password), chromiumSaltDarwin, 1003, 16, sha1.New)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	iv := bytes.Repeat([]byte{' '}, aes.BlockSize)
+	mode := cipher.NewCBCDecrypter(block, iv)
+
+	if len(ciphertext)%aes.BlockSize != 0 {
+		return nil, errors.New("browsercookie: ciphertext is not a multiple of the block size")
+	}
+
+	decrypted := make([]byte, len(ciphertext))
+	mode.CryptBlocks(decrypted, ciphertext)
+
+	return pkcs7Unpad(decrypted), nil
+}
+
+// chromiumSafeStoragePassword retrieves Chrome's Safe Storage password from the macOS Keychain.
+func chromiumSafeStoragePassword() (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-w", "-s", "Chrome Safe Storage").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func pkcs7Unpad(data []byte) []byte {
+	if len(data) == 0 {
+		return data
+	}
+	padLen := int(data[len(data)-1])
+	if padLen <= 0 || padLen > len(data) {
+		return data
+	}
+	return data[:len(data)-padLen]
+}
@@ -0,0 +1,72 @@
+//go:build linux
+
+package browsercookie
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha1"
+	"errors"
+	"golang.org/x/crypto/pbkdf2"
+	"os/exec"
+	"strings"
+)
+
+var chromiumSaltLinux = []byte("saltysalt")
+
+// chromiumDefaultLinuxPassword is the fixed password Chromium falls back to on Linux when no
+// compatible keyring (libsecret/kwallet) is available. This is documented Chromium behavior,
+// not a secret we're extracting.
+const chromiumDefaultLinuxPassword = "peanuts"
+
+// decryptChromiumValue decrypts a Chromium-on-Linux cookie value. Chrome derives an AES-128-CBC
+// key via PBKDF2 from a password stored in the system keyring (looked up here through
+// secret-tool, the libsecret CLI), falling back to Chromium's well-known default password when
+// no keyring is available.
+func decryptChromiumValue(encrypted []byte) ([]byte, error) {
+	if !bytes.HasPrefix(encrypted, []byte("v10")) && !bytes.HasPrefix(encrypted, []byte("v11")) {
+		return nil, errors.New("browsercookie: unrecognized cookie encryption version")
+	}
+	ciphertext := encrypted[3:]
+
+	password := chromiumLinuxKeyringPassword()
+
+	key := pbkdf2.Key([]byte(password), chromiumSaltLinux, 1, 16, sha1.New)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	iv := bytes.Repeat([]byte{' '}, aes.BlockSize)
+	mode := cipher.NewCBCDecrypter(block, iv)
+
+	if len(ciphertext)%aes.BlockSize != 0 {
+		return nil, errors.New("browsercookie: ciphertext is not a multiple of the block size")
+	}
+
+	decrypted := make([]byte, len(ciphertext))
+	mode.CryptBlocks(decrypted, ciphertext)
+
+	return pkcs7Unpad(decrypted), nil
+}
+
+func chromiumLinuxKeyringPassword() string {
+	out, err := exec.Command("secret-tool", "lookup", "application", "chrome").Output()
+	if err != nil || len(strings.TrimSpace(string(out))) == 0 {
+		return chromiumDefaultLinuxPassword
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func pkcs7Unpad(data []byte) []byte {
+	if len(data) == 0 {
+		return data
+	}
+	padLen := int(data[len(data)-1])
+	if padLen <= 0 || padLen > len(data) {
+		return data
+	}
+	return data[:len(data)-padLen]
+}
@@ -0,0 +1,50 @@
+//go:build windows
+
+package browsercookie
+
+import (
+	"bytes"
+	"errors"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	dllcrypt32          = syscall.NewLazyDLL("crypt32.dll")
+	dllkernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procCryptUnprotect  = dllcrypt32.NewProc("CryptUnprotectData")
+	procLocalFree       = dllkernel32.NewProc("LocalFree")
+	chromiumDpapiPrefix = []byte("v10")
+)
+
+type dataBlob struct {
+	cbData uint32
+	pbData *byte
+}
+
+// decryptChromiumValue decrypts a Chromium-on-Windows cookie value via DPAPI
+// (CryptUnprotectData). Modern Chrome versions ("v10"/"v11" prefixed values) wrap DPAPI with an
+// additional AES-GCM layer keyed by a DPAPI-protected master key stored in Local State; that
+// extra layer isn't handled here; this only unwraps the legacy plain-DPAPI case.
+func decryptChromiumValue(encrypted []byte) ([]byte, error) {
+	if bytes.HasPrefix(encrypted, chromiumDpapiPrefix) {
+		return nil, errors.New("browsercookie: AES-GCM wrapped cookie values are not supported on this platform yet")
+	}
+
+	in := dataBlob{cbData: uint32(len(encrypted)), pbData: &encrypted[0]}
+	var out dataBlob
+
+	ret, _, err := procCryptUnprotect.Call(
+		uintptr(unsafe.Pointer(&in)),
+		0, 0, 0, 0, 0,
+		uintptr(unsafe.Pointer(&out)),
+	)
+	if ret == 0 {
+		return nil, err
+	}
+	defer procLocalFree.Call(uintptr(unsafe.Pointer(out.pbData)))
+
+	decrypted := make([]byte, out.cbData)
+	copy(decrypted, unsafe.Slice(out.pbData, out.cbData))
+	return decrypted, nil
+}
@@ -0,0 +1,164 @@
+package browsercookie
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// loadFirefoxCookies reads cookies out of a Firefox profile's cookies.sqlite, filtered by host.
+func loadFirefoxCookies(src Source, hosts []string) ([]*http.Cookie, error) {
+	dbPath := src.Path
+	if dbPath == "" {
+		profileDir, err := firefoxProfileDir(src.Profile)
+		if err != nil {
+			return nil, err
+		}
+		dbPath = filepath.Join(profileDir, "cookies.sqlite")
+	}
+
+	// Firefox locks cookies.sqlite while running. Open it read-only via a private copy so we
+	// never block on (or corrupt) the live database.
+	tmpPath, err := copyToTemp(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmpPath)
+
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?mode=ro&immutable=1", tmpPath))
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT host, name, value, path, isSecure, isHttpOnly, expiry FROM moz_cookies`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cookies := make([]*http.Cookie, 0)
+	for rows.Next() {
+		var host, name, value, path string
+		var isSecure, isHttpOnly int
+		var expiry int64
+		if err := rows.Scan(&host, &name, &value, &path, &isSecure, &isHttpOnly, &expiry); err != nil {
+			continue
+		}
+		if !matchesHost(host, hosts) {
+			continue
+		}
+		cookies = append(cookies, &http.Cookie{
+			Name:     name,
+			Value:    value,
+			Domain:   host,
+			Path:     path,
+			Secure:   isSecure == 1,
+			HttpOnly: isHttpOnly == 1,
+			Expires:  time.Unix(expiry, 0),
+		})
+	}
+
+	return cookies, rows.Err()
+}
+
+// firefoxProfileDir resolves a named (or default) Firefox profile directory from profiles.ini.
+func firefoxProfileDir(profile string) (string, error) {
+	root, err := firefoxRootDir()
+	if err != nil {
+		return "", err
+	}
+
+	iniPath := filepath.Join(root, "profiles.ini")
+	content, err := os.ReadFile(iniPath)
+	if err != nil {
+		return "", err
+	}
+
+	// profiles.ini is a simple INI file; we only need Path=/Default=/Name= within each
+	// [Profile*] section, so a tiny hand-rolled parser is enough.
+	var currentPath, currentName string
+	isDefault := false
+	var defaultPath, namedPath string
+
+	flush := func() {
+		if currentPath == "" {
+			return
+		}
+		if profile != "" && currentName == profile {
+			namedPath = currentPath
+		}
+		if isDefault || defaultPath == "" {
+			defaultPath = currentPath
+		}
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			flush()
+			currentPath, currentName, isDefault = "", "", false
+			continue
+		}
+		if v, ok := strings.CutPrefix(line, "Path="); ok {
+			currentPath = v
+		} else if v, ok := strings.CutPrefix(line, "Name="); ok {
+			currentName = v
+		} else if v, ok := strings.CutPrefix(line, "Default="); ok {
+			isDefault = v == "1"
+		}
+	}
+	flush()
+
+	chosen := namedPath
+	if chosen == "" {
+		chosen = defaultPath
+	}
+	if chosen == "" {
+		return "", fmt.Errorf("browsercookie: no matching firefox profile found for %q", profile)
+	}
+
+	if filepath.IsAbs(chosen) {
+		return chosen, nil
+	}
+	return filepath.Join(root, chosen), nil
+}
+
+func firefoxRootDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	switch runtime.GOOS {
+	case "windows":
+		return filepath.Join(os.Getenv("APPDATA"), "Mozilla", "Firefox"), nil
+	case "darwin":
+		return filepath.Join(home, "Library", "Application Support", "Firefox"), nil
+	default:
+		return filepath.Join(home, ".mozilla", "firefox"), nil
+	}
+}
+
+func copyToTemp(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp("", "seanime-firefox-cookies-*.sqlite")
+	if err != nil {
+		return "", err
+	}
+	defer tmp.Close()
+	if _, err := tmp.Write(content); err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
@@ -0,0 +1,59 @@
+package extension_repo
+
+import (
+	"github.com/rs/zerolog"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"seanime/internal/extension_repo/browsercookie"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// buildExtensionCookieJar seeds a fresh http.CookieJar from the user's local browser, scoped to
+// the hosts the extension's manifest declared via "browserCookies". profile selects the
+// browser/profile to read from (e.g. "firefox:default-release"); an empty profile defaults to
+// Chromium's default profile.
+//
+// This is opt-in: an extension with no browserCookies declared never touches the local browser.
+func buildExtensionCookieJar(hosts []string, profile string, logger *zerolog.Logger) (http.CookieJar, error) {
+	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(hosts) == 0 {
+		return jar, nil
+	}
+
+	if profile == "" {
+		profile = string(browsercookie.BrowserChromium)
+	}
+
+	src, err := browsercookie.ParseSource(profile)
+	if err != nil {
+		logger.Warn().Err(err).Str("profile", profile).Msg("extensions: failed to parse browserCookies source")
+		return jar, nil
+	}
+
+	cookies, err := browsercookie.Load(src, hosts)
+	if err != nil {
+		logger.Warn().Err(err).Str("profile", profile).Msg("extensions: failed to read browser cookies")
+		return jar, nil
+	}
+
+	byHost := make(map[string][]*http.Cookie)
+	for _, c := range cookies {
+		host := c.Domain
+		byHost[host] = append(byHost[host], c)
+	}
+
+	for host, hostCookies := range byHost {
+		u := &url.URL{Scheme: "https", Host: host}
+		jar.SetCookies(u, hostCookies)
+	}
+
+	logger.Debug().Int("count", len(cookies)).Str("profile", profile).Msg("extensions: seeded cookie jar from browser")
+
+	return jar, nil
+}
@@ -9,6 +9,7 @@ import (
 	gojaurl "github.com/dop251/goja_nodejs/url"
 	"github.com/evanw/esbuild/pkg/api"
 	"github.com/rs/zerolog"
+	"net/http"
 	"seanime/internal/extension"
 )
 
@@ -16,7 +17,20 @@ import (
 func SetupGojaExtensionVM(ext *extension.Extension, language extension.Language, logger *zerolog.Logger) (*goja.Runtime, error) {
 	logger.Trace().Str("id", ext.ID).Any("language", language).Msgf("extensions: Creating javascript VM for external manga provider")
 
-	vm, err := CreateJSVM()
+	// Extensions can opt into reusing the user's real browser session (cookies) by declaring
+	// "browserCookies" in their manifest - useful against providers behind Cloudflare or
+	// requiring an authenticated session. Cookies are re-read fresh on every VM reset.
+	var jar http.CookieJar
+	if len(ext.BrowserCookies) > 0 {
+		var jarErr error
+		jar, jarErr = buildExtensionCookieJar(ext.BrowserCookies, ext.CookieProfile, logger)
+		if jarErr != nil {
+			logger.Warn().Err(jarErr).Str("id", ext.ID).Msg("extensions: failed to build browser cookie jar, continuing without it")
+			jar = nil
+		}
+	}
+
+	vm, err := CreateJSVM(jar)
 	if err != nil {
 		logger.Error().Err(err).Str("id", ext.ID).Msg("extensions: Failed to create javascript VM")
 		return nil, err
@@ -42,8 +56,9 @@ func SetupGojaExtensionVM(ext *extension.Extension, language extension.Language,
 	return vm, nil
 }
 
-// CreateJSVM creates a new JavaScript VM for SetupGojaExtensionVM
-func CreateJSVM() (*goja.Runtime, error) {
+// CreateJSVM creates a new JavaScript VM for SetupGojaExtensionVM. jar is optional - pass nil
+// for the default, cookie-less fetch behavior.
+func CreateJSVM(jar http.CookieJar) (*goja.Runtime, error) {
 
 	vm := goja.New()
 	vm.SetParserOptions(parser.WithDisableSourceMaps)
@@ -54,7 +69,7 @@ func CreateJSVM() (*goja.Runtime, error) {
 	gojaurl.Enable(vm)
 	gojaconsole.Enable(vm)
 
-	err := gojaBindFetch(vm)
+	err := gojaBindFetch(vm, jar)
 	if err != nil {
 		return nil, err
 	}
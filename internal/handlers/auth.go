@@ -4,7 +4,7 @@ import (
 	"context"
 	"errors"
 	"github.com/gofiber/fiber/v2"
-	"github.com/seanime-app/seanime-server/internal/models"
+	"seanime/internal/models"
 	"time"
 )
 
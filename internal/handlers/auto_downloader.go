@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"errors"
+)
+
+type downloadSpecificEpisodesBody struct {
+	MediaId         int    `json:"mediaId"`
+	EpisodeSelector string `json:"episodeSelector"` // e.g. "12", "12-34", "50-", "latest"
+}
+
+// HandleDownloadSpecificEpisodes
+//
+//	@summary downloads specific episodes for a media, bypassing the auto-downloader rule engine.
+//	@desc Lets users grab a single missing episode or backfill a specific arc without creating a rule.
+//	@route /api/v1/auto-downloader/download-episodes [POST]
+//	@returns bool
+func HandleDownloadSpecificEpisodes(c *RouteCtx) error {
+
+	b := new(downloadSpecificEpisodesBody)
+	if err := c.Fiber.BodyParser(b); err != nil {
+		return c.RespondWithError(err)
+	}
+
+	if b.MediaId == 0 {
+		return c.RespondWithError(errors.New("'mediaId' is required"))
+	}
+	if len(b.EpisodeSelector) == 0 {
+		return c.RespondWithError(errors.New("'episodeSelector' is required"))
+	}
+
+	if err := c.App.AutoDownloader.DownloadSpecificEpisodes(b.MediaId, b.EpisodeSelector); err != nil {
+		return c.RespondWithError(err)
+	}
+
+	return c.RespondWithData(true)
+}
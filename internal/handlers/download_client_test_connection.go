@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"seanime/internal/torrents/downloadclient"
+)
+
+type testDownloadClientConnectionBody struct {
+	ClientType string `json:"clientType"`
+	Host       string `json:"host"`
+	Port       int    `json:"port"`
+	Username   string `json:"username"`
+	Password   string `json:"password"`
+}
+
+// HandleTestDownloadClientConnection
+//
+//	@summary probes connectivity and authentication against a download client backend.
+//	@desc Used by the settings UI to validate qBittorrent/Aria2 connection details before saving
+//	@desc them - never actually saves anything.
+//	@route /api/v1/settings/download-client/test [POST]
+//	@returns downloadclient.Capabilities
+func HandleTestDownloadClientConnection(c *RouteCtx) error {
+
+	c.AcceptJSON()
+
+	b := new(testDownloadClientConnectionBody)
+	if err := c.Fiber.BodyParser(b); err != nil {
+		return c.RespondWithError(err)
+	}
+
+	if len(b.ClientType) == 0 {
+		return c.RespondWithError(errors.New("'clientType' is required"))
+	}
+
+	var (
+		client downloadclient.DownloadClient
+		err    error
+	)
+
+	switch downloadclient.ClientType(b.ClientType) {
+	case downloadclient.ClientTypeQbittorrent:
+		client, err = downloadclient.NewQbittorrentClient(downloadclient.QbittorrentConfig{
+			Host:     b.Host,
+			Port:     b.Port,
+			Username: b.Username,
+			Password: b.Password,
+		}, c.App.Logger)
+	case downloadclient.ClientTypeAria2:
+		client, err = downloadclient.NewAria2Client(downloadclient.Aria2Config{
+			Host:   b.Host,
+			Port:   b.Port,
+			Secret: b.Password,
+		}, c.App.Logger)
+	default:
+		return c.RespondWithError(errors.New("unsupported 'clientType' for a connection test"))
+	}
+
+	if err != nil {
+		return c.RespondWithError(err)
+	}
+
+	return c.RespondWithData(client.Capabilities())
+}
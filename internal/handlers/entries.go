@@ -1,8 +1,8 @@
 package handlers
 
 import (
-	"github.com/seanime-app/seanime-server/internal/constants"
-	"github.com/seanime-app/seanime-server/internal/entities"
+	"seanime/internal/constants"
+	"seanime/internal/entities"
 )
 
 type mediaEntryQuery struct {
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	chapter_downloader "seanime/internal/manga/downloader"
+)
+
+type enqueueMangaChapterBody struct {
+	Provider  string `json:"provider"`
+	MediaId   int    `json:"mediaId"`
+	ChapterId string `json:"chapterId"`
+}
+
+// HandleEnqueueMangaChapter
+//
+//	@summary adds a chapter to the download queue.
+//	@route /api/v1/manga/download-queue [POST]
+//	@returns models.DownloadQueueItem
+func HandleEnqueueMangaChapter(c *RouteCtx) error {
+
+	b := new(enqueueMangaChapterBody)
+	if err := c.Fiber.BodyParser(b); err != nil {
+		return c.RespondWithError(err)
+	}
+
+	item, err := c.App.MangaDownloadQueue.Enqueue(chapter_downloader.QueueItemOptions{
+		Provider:  b.Provider,
+		MediaId:   b.MediaId,
+		ChapterId: b.ChapterId,
+	})
+	if err != nil {
+		return c.RespondWithError(err)
+	}
+
+	return c.RespondWithData(item)
+}
+
+// HandleGetMangaDownloadQueue
+//
+//	@summary returns the current download queue.
+//	@route /api/v1/manga/download-queue [GET]
+//	@returns []models.DownloadQueueItem
+func HandleGetMangaDownloadQueue(c *RouteCtx) error {
+
+	items, err := c.App.Database.GetDownloadQueueItems()
+	if err != nil {
+		return c.RespondWithError(err)
+	}
+
+	return c.RespondWithData(items)
+}
+
+// HandlePauseMangaDownloadQueue
+//
+//	@summary pauses every queued or in-progress download.
+//	@route /api/v1/manga/download-queue/pause [POST]
+//	@returns bool
+func HandlePauseMangaDownloadQueue(c *RouteCtx) error {
+	c.App.MangaDownloadQueue.PauseAll()
+	return c.RespondWithData(true)
+}
+
+// HandleResumeMangaDownloadQueue
+//
+//	@summary resumes every paused download.
+//	@route /api/v1/manga/download-queue/resume [POST]
+//	@returns bool
+func HandleResumeMangaDownloadQueue(c *RouteCtx) error {
+	c.App.MangaDownloadQueue.ResumeAll()
+	return c.RespondWithData(true)
+}
+
+type cancelMangaDownloadBody struct {
+	ID uint `json:"id"`
+}
+
+// HandleCancelMangaDownload
+//
+//	@summary removes an item from the download queue, cancelling it if in progress.
+//	@route /api/v1/manga/download-queue/cancel [POST]
+//	@returns bool
+func HandleCancelMangaDownload(c *RouteCtx) error {
+
+	b := new(cancelMangaDownloadBody)
+	if err := c.Fiber.BodyParser(b); err != nil {
+		return c.RespondWithError(err)
+	}
+
+	if err := c.App.MangaDownloadQueue.Cancel(b.ID); err != nil {
+		return c.RespondWithError(err)
+	}
+
+	return c.RespondWithData(true)
+}
+
+type reorderMangaDownloadQueueBody struct {
+	OrderedIds []uint `json:"orderedIds"`
+}
+
+// HandleReorderMangaDownloadQueue
+//
+//	@summary changes the processing order of the download queue.
+//	@route /api/v1/manga/download-queue/reorder [POST]
+//	@returns bool
+func HandleReorderMangaDownloadQueue(c *RouteCtx) error {
+
+	b := new(reorderMangaDownloadQueueBody)
+	if err := c.Fiber.BodyParser(b); err != nil {
+		return c.RespondWithError(err)
+	}
+
+	if err := c.App.MangaDownloadQueue.Reorder(b.OrderedIds); err != nil {
+		return c.RespondWithError(err)
+	}
+
+	return c.RespondWithData(true)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"seanime/internal/manga"
+	chapter_downloader "seanime/internal/manga/downloader"
+	manga_providers "seanime/internal/manga/providers"
+)
+
+type exportMangaChapterBody struct {
+	Provider      string `json:"provider"`
+	MediaId       int    `json:"mediaId"`
+	ChapterId     string `json:"chapterId"`
+	ChapterNumber string `json:"chapterNumber"`
+	Format        string `json:"format"` // "cbz" or "epub"
+}
+
+// HandleExportMangaChapter
+//
+//	@summary exports a downloaded manga chapter to CBZ or EPUB.
+//	@desc Fetches the media title from AniList and writes it into ComicInfo.xml.
+//	@route /api/v1/manga/export [POST]
+//	@returns string
+func HandleExportMangaChapter(c *RouteCtx) error {
+
+	b := new(exportMangaChapterBody)
+	if err := c.Fiber.BodyParser(b); err != nil {
+		return c.RespondWithError(err)
+	}
+
+	media, err := c.App.AnilistClientWrapper.BaseMediaByID(c.Fiber.Context(), &b.MediaId)
+	if err != nil {
+		return c.RespondWithError(err)
+	}
+
+	archivePath, err := c.App.MangaRepository.ExportChapter(manga.ExportChapterOptions{
+		Provider:      manga_providers.Provider(b.Provider),
+		MediaId:       b.MediaId,
+		ChapterId:     b.ChapterId,
+		Format:        chapter_downloader.ExportFormat(b.Format),
+		SeriesTitle:   media.GetTitleSafe(),
+		ChapterNumber: b.ChapterNumber,
+	})
+	if err != nil {
+		return c.RespondWithError(err)
+	}
+
+	return c.RespondWithData(archivePath)
+}
+
+type exportMangaEntryBody struct {
+	MediaId int    `json:"mediaId"`
+	Format  string `json:"format"` // "cbz" or "epub"
+}
+
+// HandleExportMangaEntry
+//
+//	@summary exports every downloaded chapter of a media entry into a single archive.
+//	@route /api/v1/manga/export/bulk [POST]
+//	@returns string
+func HandleExportMangaEntry(c *RouteCtx) error {
+
+	b := new(exportMangaEntryBody)
+	if err := c.Fiber.BodyParser(b); err != nil {
+		return c.RespondWithError(err)
+	}
+
+	media, err := c.App.AnilistClientWrapper.BaseMediaByID(c.Fiber.Context(), &b.MediaId)
+	if err != nil {
+		return c.RespondWithError(err)
+	}
+
+	bundlePath, err := c.App.MangaRepository.ExportMediaEntry(manga.ExportMediaEntryOptions{
+		MediaId:     b.MediaId,
+		Format:      chapter_downloader.ExportFormat(b.Format),
+		SeriesTitle: media.GetTitleSafe(),
+	})
+	if err != nil {
+		return c.RespondWithError(err)
+	}
+
+	return c.RespondWithData(bundlePath)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+type mediaRecommendationsQuery struct {
+	MediaId int `query:"mediaId" json:"mediaId"`
+}
+
+// HandleGetMediaRecommendations
+//
+//	@summary returns a ranked, de-duplicated list of recommendations for a media entry.
+//	@desc Blends AniList's recommendation edges with MAL's related-anime data and filters out
+//	@desc anything the user has already watched.
+//	@route /api/v1/media-entry/recommendations [GET]
+//	@returns []recommendation.Recommendation
+func HandleGetMediaRecommendations(c *RouteCtx) error {
+
+	p := new(mediaRecommendationsQuery)
+	if err := c.Fiber.QueryParser(p); err != nil {
+		return c.RespondWithError(err)
+	}
+
+	media, err := c.App.AnilistClientWrapper.Client.BaseMediaByID(c.Fiber.Context(), &p.MediaId)
+	if err != nil {
+		return c.RespondWithError(err)
+	}
+
+	watchedMediaIds := make(map[int]struct{})
+	if anilistCollection, err := c.App.GetAnilistCollection(false); err == nil && anilistCollection != nil {
+		for _, list := range anilistCollection.MediaListCollection.GetLists() {
+			for _, entry := range list.GetEntries() {
+				if entry.GetMedia() != nil {
+					watchedMediaIds[entry.GetMedia().GetID()] = struct{}{}
+				}
+			}
+		}
+	}
+
+	recommendations, err := c.App.Recommendations.GetRecommendations(p.MediaId, media.GetMedia().GetTitleSafe(), watchedMediaIds)
+	if err != nil {
+		return c.RespondWithError(err)
+	}
+
+	return c.RespondWithData(recommendations)
+}
@@ -2,12 +2,15 @@ package handlers
 
 import (
 	"errors"
-	"github.com/seanime-app/seanime-server/internal/scanner"
+	"seanime/internal/scanner"
 )
 
 type ScanRequestBody struct {
 	Username string `json:"username"`
 	Enhanced bool   `json:"enhanced"`
+	// Mode selects how much of the pipeline re-runs against each file: "" (full, the default),
+	// "incremental", or "quick". See scanner.ScanMode.
+	Mode string `json:"mode"`
 }
 
 func HandleScanLocalFiles(c *RouteCtx) error {
@@ -37,6 +40,7 @@ func HandleScanLocalFiles(c *RouteCtx) error {
 		DirPath:       libraryPath,
 		Username:      body.Username,
 		Enhanced:      body.Enhanced,
+		Mode:          scanner.ScanMode(body.Mode),
 		AnilistClient: c.App.AnilistClient,
 		Logger:        c.App.Logger,
 		DB:            c.App.Database,
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+)
+
+type schedulePreviewQuery struct {
+	Task  string `query:"task"`
+	Count int    `query:"count"`
+}
+
+// HandleGetSchedulePreview
+//
+//	@summary returns the next N scheduled fire times for a task.
+//	@desc Lets the settings UI preview a cron expression before saving it.
+//	@route /api/v1/scheduler/preview [GET]
+//	@returns []time.Time
+func HandleGetSchedulePreview(c *RouteCtx) error {
+
+	p := new(schedulePreviewQuery)
+	if err := c.Fiber.QueryParser(p); err != nil {
+		return c.RespondWithError(err)
+	}
+
+	if len(p.Task) == 0 {
+		return c.RespondWithError(errors.New("'task' is required"))
+	}
+	if p.Count <= 0 {
+		p.Count = 5
+	}
+
+	times, err := c.App.Scheduler.NextFireTimes(p.Task, p.Count)
+	if err != nil {
+		return c.RespondWithError(err)
+	}
+
+	return c.RespondWithData(times)
+}
+
+type runScheduledTaskNowBody struct {
+	Task string `json:"task"`
+}
+
+// HandleRunScheduledTaskNow
+//
+//	@summary triggers a scheduled task immediately, without affecting its regular schedule.
+//	@route /api/v1/scheduler/run-now [POST]
+//	@returns bool
+func HandleRunScheduledTaskNow(c *RouteCtx) error {
+
+	c.AcceptJSON()
+
+	b := new(runScheduledTaskNowBody)
+	if err := c.Fiber.BodyParser(b); err != nil {
+		return c.RespondWithError(err)
+	}
+
+	if len(b.Task) == 0 {
+		return c.RespondWithError(errors.New("'task' is required"))
+	}
+
+	if err := c.App.Scheduler.RunNow(b.Task); err != nil {
+		return c.RespondWithError(err)
+	}
+
+	return c.RespondWithData(true)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"runtime"
 	"seanime/internal/database/models"
+	"seanime/internal/scheduler"
 	"seanime/internal/torrents/torrent"
 	"seanime/internal/util"
 	"time"
@@ -46,6 +47,9 @@ func HandleGettingStarted(c *RouteCtx) error {
 		Notifications          models.NotificationSettings `json:"notifications"`
 		EnableTranscode        bool                        `json:"enableTranscode"`
 		EnableTorrentStreaming bool                        `json:"enableTorrentStreaming"`
+		// TorrentstreamWebseedURLs is a comma-separated list of HTTP mirrors (BEP19 web seeds),
+		// parsed the same way as other CLI-style array fields and saved as WebseedURLs.
+		TorrentstreamWebseedURLs string `json:"torrentstreamWebseedUrls"`
 	}
 	var b body
 
@@ -53,11 +57,25 @@ func HandleGettingStarted(c *RouteCtx) error {
 		return c.RespondWithError(err)
 	}
 
+	if err := validateSettingsBody(b.Library); err != nil {
+		return c.RespondWithError(err)
+	}
+
+	// Snapshot whatever settings row exists (there may be none yet, on a fresh install) so this
+	// save can be rolled back if a module fails to initialize against it below.
+	previous, _ := c.App.Database.GetSettings()
+	if previous != nil && previous.ID != 0 {
+		if _, err := c.App.Database.CreateSettingsRevision(previous); err != nil {
+			c.App.Logger.Warn().Err(err).Msg("settings: failed to snapshot previous revision")
+		}
+	}
+
 	settings, err := c.App.Database.UpsertSettings(&models.Settings{
 		BaseModel: models.BaseModel{
 			ID:        1,
 			UpdatedAt: time.Now(),
 		},
+		SchemaVersion: currentSettingsSchemaVersion,
 		Library:       &b.Library,
 		MediaPlayer:   &b.MediaPlayer,
 		Torrent:       &b.Torrent,
@@ -84,6 +102,7 @@ func HandleGettingStarted(c *RouteCtx) error {
 			prev, found := c.App.Database.GetTorrentstreamSettings()
 			if found {
 				prev.Enabled = true
+				prev.WebseedURLs = util.CliString2Array(b.TorrentstreamWebseedURLs)
 				_, _ = c.App.Database.UpsertTorrentstreamSettings(prev)
 			}
 		}()
@@ -104,8 +123,15 @@ func HandleGettingStarted(c *RouteCtx) error {
 
 	status := NewStatus(c)
 
-	// Refresh modules that depend on the settings
-	c.App.InitOrRefreshModules()
+	// Refresh modules that depend on the settings. Among other things, this tears down and
+	// rebuilds the library Watcher based on Library.AutoScanEnabled (and DirPath, should it
+	// have changed), rebuilds the AutoDownloader/TorrentstreamRepository's DownloadClient
+	// from Torrent.ClientType/Host/Port/Username/Password via downloadclient.NewFromSettings,
+	// and re-registers scheduler.TaskLibraryScan from Library.ScanCronExpr. If any of that panics,
+	// the save is rolled back to the snapshot taken above instead of leaving the app half-wired.
+	if err := refreshModulesOrRollback(c, previous); err != nil {
+		return c.RespondWithError(err)
+	}
 
 	return c.RespondWithData(status)
 }
@@ -127,6 +153,9 @@ func HandleSaveSettings(c *RouteCtx) error {
 		Discord       models.DiscordSettings      `json:"discord"`
 		Manga         models.MangaSettings        `json:"manga"`
 		Notifications models.NotificationSettings `json:"notifications"`
+		// TorrentstreamWebseedURLs is a comma-separated list of HTTP mirrors (BEP19 web seeds),
+		// parsed the same way as other CLI-style array fields and saved as WebseedURLs.
+		TorrentstreamWebseedURLs string `json:"torrentstreamWebseedUrls"`
 	}
 	var b body
 
@@ -134,6 +163,10 @@ func HandleSaveSettings(c *RouteCtx) error {
 		return c.RespondWithError(err)
 	}
 
+	if err := validateSettingsBody(b.Library); err != nil {
+		return c.RespondWithError(err)
+	}
+
 	autoDownloaderSettings := models.AutoDownloaderSettings{}
 	prevSettings, err := c.App.Database.GetSettings()
 	if err == nil && prevSettings.AutoDownloader != nil {
@@ -145,11 +178,20 @@ func HandleSaveSettings(c *RouteCtx) error {
 		autoDownloaderSettings.Enabled = false
 	}
 
+	// Snapshot the row being overwritten so this save can be rolled back if a module fails to
+	// initialize against it below.
+	if prevSettings != nil && prevSettings.ID != 0 {
+		if _, err := c.App.Database.CreateSettingsRevision(prevSettings); err != nil {
+			c.App.Logger.Warn().Err(err).Msg("settings: failed to snapshot previous revision")
+		}
+	}
+
 	settings, err := c.App.Database.UpsertSettings(&models.Settings{
 		BaseModel: models.BaseModel{
 			ID:        1,
 			UpdatedAt: time.Now(),
 		},
+		SchemaVersion:  currentSettingsSchemaVersion,
 		Library:        &b.Library,
 		MediaPlayer:    &b.MediaPlayer,
 		Torrent:        &b.Torrent,
@@ -166,10 +208,25 @@ func HandleSaveSettings(c *RouteCtx) error {
 
 	c.App.WSEventManager.SendEvent("settings", settings)
 
+	// Update torrent-streaming webseed mirrors. This doesn't go through InitOrRefreshModules
+	// below since TorrentstreamSettings is stored separately from the main Settings row - the
+	// list is only stored here; no stream start reads WebseedURLs.
+	if tsSettings, found := c.App.Database.GetTorrentstreamSettings(); found {
+		tsSettings.WebseedURLs = util.CliString2Array(b.TorrentstreamWebseedURLs)
+		_, _ = c.App.Database.UpsertTorrentstreamSettings(tsSettings)
+	}
+
 	status := NewStatus(c)
 
-	// Refresh modules that depend on the settings
-	c.App.InitOrRefreshModules()
+	// Refresh modules that depend on the settings. Among other things, this tears down and
+	// rebuilds the library Watcher based on Library.AutoScanEnabled (and DirPath, should it
+	// have changed), rebuilds the AutoDownloader/TorrentstreamRepository's DownloadClient
+	// from Torrent.ClientType/Host/Port/Username/Password via downloadclient.NewFromSettings,
+	// and re-registers scheduler.TaskLibraryScan from Library.ScanCronExpr. If any of that panics,
+	// the save is rolled back to the snapshot taken above instead of leaving the app half-wired.
+	if err := refreshModulesOrRollback(c, prevSettings); err != nil {
+		return c.RespondWithError(err)
+	}
 
 	return c.RespondWithData(status)
 }
@@ -182,10 +239,11 @@ func HandleSaveSettings(c *RouteCtx) error {
 func HandleSaveAutoDownloaderSettings(c *RouteCtx) error {
 
 	type body struct {
-		Interval              int  `json:"interval"`
-		Enabled               bool `json:"enabled"`
-		DownloadAutomatically bool `json:"downloadAutomatically"`
-		EnableEnhancedQueries bool `json:"enableEnhancedQueries"`
+		Interval              int    `json:"interval"`
+		CronExpr              string `json:"cronExpr"` // takes precedence over Interval when set
+		Enabled               bool   `json:"enabled"`
+		DownloadAutomatically bool   `json:"downloadAutomatically"`
+		EnableEnhancedQueries bool   `json:"enableEnhancedQueries"`
 	}
 
 	var b body
@@ -199,20 +257,34 @@ func HandleSaveAutoDownloaderSettings(c *RouteCtx) error {
 		return c.RespondWithError(err)
 	}
 
-	// Validation
-	if b.Interval < 2 {
+	// Validation - a cron expression, when provided, replaces the fixed interval entirely and
+	// is validated on its own terms; otherwise we keep enforcing the 2-minute minimum interval.
+	if len(b.CronExpr) > 0 {
+		if err := scheduler.ValidateCronExpr(b.CronExpr); err != nil {
+			return c.RespondWithError(err)
+		}
+	} else if b.Interval < 2 {
 		return c.RespondWithError(errors.New("interval must be at least 2 minutes"))
 	}
 
 	autoDownloaderSettings := &models.AutoDownloaderSettings{
 		Provider:              currSettings.Library.TorrentProvider,
 		Interval:              b.Interval,
+		CronExpr:              b.CronExpr,
 		Enabled:               b.Enabled,
 		DownloadAutomatically: b.DownloadAutomatically,
 		EnableEnhancedQueries: b.EnableEnhancedQueries,
 	}
 
+	// Snapshot the row being overwritten, same as the other settings handlers.
+	if currSettings.ID != 0 {
+		if _, err := c.App.Database.CreateSettingsRevision(currSettings); err != nil {
+			c.App.Logger.Warn().Err(err).Msg("settings: failed to snapshot previous revision")
+		}
+	}
+
 	currSettings.AutoDownloader = autoDownloaderSettings
+	currSettings.SchemaVersion = currentSettingsSchemaVersion
 	currSettings.BaseModel = models.BaseModel{
 		ID:        1,
 		UpdatedAt: time.Now(),
@@ -226,5 +298,42 @@ func HandleSaveAutoDownloaderSettings(c *RouteCtx) error {
 	// Update Auto Downloader - This runs in a goroutine
 	c.App.AutoDownloader.SetSettings(autoDownloaderSettings, currSettings.Library.TorrentProvider)
 
+	// Re-register (or unregister) the auto-downloader's scheduler.TaskAutoDownloader entry from
+	// CronExpr. A fixed Interval doesn't go through the Scheduler - AutoDownloader still ticks
+	// that one on its own, same as before CronExpr existed.
+	if len(autoDownloaderSettings.CronExpr) > 0 {
+		if err := c.App.Scheduler.Register(scheduler.TaskAutoDownloader, autoDownloaderSettings.CronExpr, c.App.AutoDownloader.Run); err != nil {
+			c.App.Logger.Warn().Err(err).Msg("settings: failed to register auto-downloader schedule")
+		}
+	} else {
+		c.App.Scheduler.Unregister(scheduler.TaskAutoDownloader)
+	}
+
 	return c.RespondWithData(true)
 }
+
+// HandleSetHTTPCachePolicy
+//
+//	@summary enables or disables the shared HTTP response cache used by scrapers.
+//	@desc Useful when debugging scraper regressions without restarting the app.
+//	@route /api/v1/settings/http-cache [PATCH]
+//	@returns bool
+func HandleSetHTTPCachePolicy(c *RouteCtx) error {
+
+	type body struct {
+		Enabled bool `json:"enabled"`
+	}
+	var b body
+
+	if err := c.Fiber.BodyParser(&b); err != nil {
+		return c.RespondWithError(err)
+	}
+
+	if b.Enabled {
+		c.App.EnableHTTPCache()
+	} else {
+		c.App.DisableHTTPCache()
+	}
+
+	return c.RespondWithData(c.App.IsHTTPCacheEnabled())
+}
@@ -0,0 +1,47 @@
+package handlers
+
+// HandleGetSettingsRevisions
+//
+//	@summary returns the kept settings snapshots, most recent first.
+//	@desc Each revision is taken automatically right before a settings save overwrites the row,
+//	@desc so this doubles as an audit trail for multi-user setups.
+//	@route /api/v1/settings/revisions [GET]
+//	@returns []models.SettingsRevision
+func HandleGetSettingsRevisions(c *RouteCtx) error {
+
+	revisions, err := c.App.Database.GetSettingsRevisions()
+	if err != nil {
+		return c.RespondWithError(err)
+	}
+
+	return c.RespondWithData(revisions)
+}
+
+// HandleRestoreSettingsRevision
+//
+//	@summary rolls the settings back to a previously kept revision.
+//	@desc Refreshes every module against the restored settings the same way a normal save does.
+//	@route /api/v1/settings/revisions/{id}/restore [POST]
+//	@returns handlers.Status
+func HandleRestoreSettingsRevision(c *RouteCtx) error {
+
+	id, err := c.Fiber.ParamsInt("id")
+	if err != nil {
+		return c.RespondWithError(err)
+	}
+
+	previous, err := c.App.Database.GetSettings()
+	if err != nil {
+		return c.RespondWithError(err)
+	}
+
+	if _, err := c.App.Database.RestoreSettingsRevision(uint(id)); err != nil {
+		return c.RespondWithError(err)
+	}
+
+	if err := refreshModulesOrRollback(c, previous); err != nil {
+		return c.RespondWithError(err)
+	}
+
+	return c.RespondWithData(NewStatus(c))
+}
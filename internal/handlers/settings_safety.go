@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"fmt"
+	"seanime/internal/database/models"
+)
+
+// currentSettingsSchemaVersion is stamped on every settings row written by this version of the
+// app. SettingsRevision keeps a copy of it alongside each snapshot so a restore can tell whether
+// it predates a schema change that would need migrating forward.
+const currentSettingsSchemaVersion = 1
+
+// validateSettingsBody rejects a settings save before it's written or handed to
+// InitOrRefreshModules. It only covers what can be checked without depending on modules outside
+// this package (e.g. reaching out to the configured media player) - deeper checks belong to the
+// module that owns that setting.
+func validateSettingsBody(library models.LibrarySettings) error {
+	if len(library.TorrentProvider) == 0 {
+		return fmt.Errorf("settings: 'library.torrentProvider' is required")
+	}
+	return nil
+}
+
+// refreshModulesOrRollback calls InitOrRefreshModules and, if it panics - e.g. a download client
+// can't bind its configured port, or Discord RPC fails to authenticate - restores previous as the
+// live settings row and rebuilds modules against it before reporting the failure. This keeps one
+// bad save from soft-bricking the app until the user can fix it from a shell.
+func refreshModulesOrRollback(c *RouteCtx, previous *models.Settings) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.App.Logger.Error().Interface("panic", r).Msg("settings: module refresh failed, rolling back to the previous revision")
+
+			// previous is nil on the fresh-install path (no settings row existed yet to roll back
+			// to) - fall back to a default row instead of handing UpsertSettings a nil pointer.
+			if previous == nil {
+				previous = &models.Settings{}
+			}
+
+			if _, rbErr := c.App.Database.UpsertSettings(previous); rbErr != nil {
+				err = fmt.Errorf("settings rejected (%v), and the automatic rollback also failed: %w", r, rbErr)
+				return
+			}
+
+			// Best-effort: rebuild modules against the restored settings. If this also panics,
+			// the settings row is still correctly rolled back - only module state may lag until
+			// the app is restarted.
+			func() {
+				defer func() { _ = recover() }()
+				c.App.InitOrRefreshModules()
+			}()
+
+			err = fmt.Errorf("settings rejected: %v - reverted to the previous configuration", r)
+		}
+	}()
+
+	c.App.InitOrRefreshModules()
+	return nil
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"errors"
+	"seanime/internal/torrents/webseed"
+)
+
+type testTorrentstreamWebseedsBody struct {
+	URLs []string `json:"urls"`
+}
+
+// HandleTestTorrentstreamWebseeds
+//
+//	@summary validates HTTP mirrors before they're saved as torrent-streaming web seeds.
+//	@desc HEADs each URL and checks for "Accept-Ranges: bytes" - a mirror that can't serve ranged
+//	@desc requests can't be used as a BEP19 web seed, even if it's otherwise reachable.
+//	@route /api/v1/torrentstream/webseed/test [POST]
+//	@returns []webseed.Status
+func HandleTestTorrentstreamWebseeds(c *RouteCtx) error {
+
+	c.AcceptJSON()
+
+	b := new(testTorrentstreamWebseedsBody)
+	if err := c.Fiber.BodyParser(b); err != nil {
+		return c.RespondWithError(err)
+	}
+
+	if len(b.URLs) == 0 {
+		return c.RespondWithError(errors.New("'urls' is required"))
+	}
+
+	return c.RespondWithData(webseed.TestURLs(b.URLs))
+}
@@ -0,0 +1,261 @@
+package chapter_downloader
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"seanime/internal/database"
+	"seanime/internal/database/models"
+	"seanime/internal/events"
+	"sync"
+	"time"
+)
+
+type (
+	// QueueProgressEvent is emitted over the websocket as a chapter downloads, so the client can
+	// render a progress bar for each queued item.
+	QueueProgressEvent struct {
+		ChapterId  string                        `json:"chapterId"`
+		Page       int                           `json:"page"`
+		TotalPages int                           `json:"totalPages"`
+		Bytes      int64                         `json:"bytes"`
+		State      models.DownloadQueueItemState `json:"state"`
+	}
+
+	// QueueItemOptions describes a chapter to enqueue for download.
+	QueueItemOptions struct {
+		Provider  string
+		MediaId   int
+		ChapterId string
+	}
+
+	// Queue is a background download manager that processes one chapter at a time, persisting
+	// its state to the database so items survive restarts.
+	Queue struct {
+		db             *database.Database
+		wsEventManager events.IWSEventManager
+		logger         *zerolog.Logger
+		downloadFn     func(ctx context.Context, item *models.DownloadQueueItem, onProgress func(page, totalPages int, bytes int64)) error
+
+		mu       sync.Mutex
+		paused   bool
+		wakeCh   chan struct{}
+		cancels  map[uint]context.CancelFunc
+		stopOnce sync.Once
+		stopCh   chan struct{}
+	}
+)
+
+const maxDownloadRetries = 5
+
+// NewQueue creates a new download Queue. downloadFn performs the actual download of a single
+// chapter's pages, reporting progress via onProgress.
+func NewQueue(
+	db *database.Database,
+	wsEventManager events.IWSEventManager,
+	logger *zerolog.Logger,
+	downloadFn func(ctx context.Context, item *models.DownloadQueueItem, onProgress func(page, totalPages int, bytes int64)) error,
+) *Queue {
+	q := &Queue{
+		db:             db,
+		wsEventManager: wsEventManager,
+		logger:         logger,
+		downloadFn:     downloadFn,
+		wakeCh:         make(chan struct{}, 1),
+		cancels:        make(map[uint]context.CancelFunc),
+		stopCh:         make(chan struct{}),
+	}
+
+	go q.run()
+	// Automatically resume anything that was mid-flight when the app last stopped.
+	q.ResumeAll()
+
+	return q
+}
+
+// Enqueue adds a chapter to the end of the queue.
+func (q *Queue) Enqueue(opts QueueItemOptions) (*models.DownloadQueueItem, error) {
+	items, err := q.db.GetDownloadQueueItems()
+	if err != nil {
+		return nil, err
+	}
+
+	item := &models.DownloadQueueItem{
+		Provider:  opts.Provider,
+		MediaId:   opts.MediaId,
+		ChapterId: opts.ChapterId,
+		State:     models.DownloadQueueItemStateQueued,
+		Position:  len(items),
+	}
+
+	if err := q.db.InsertDownloadQueueItem(item); err != nil {
+		return nil, err
+	}
+
+	q.wake()
+
+	return item, nil
+}
+
+// PauseAll pauses every queued or downloading item. In-flight downloads are cancelled and
+// re-queued as "paused" so they can be resumed later without re-downloading from scratch.
+func (q *Queue) PauseAll() {
+	q.mu.Lock()
+	q.paused = true
+	for _, cancel := range q.cancels {
+		cancel()
+	}
+	q.mu.Unlock()
+
+	items, err := q.db.GetDownloadQueueItems()
+	if err != nil {
+		q.logger.Error().Err(err).Msg("chapter_downloader: failed to load queue items to pause")
+		return
+	}
+	for _, item := range items {
+		if item.State == models.DownloadQueueItemStateQueued || item.State == models.DownloadQueueItemStateDownloading {
+			item.State = models.DownloadQueueItemStatePaused
+			_ = q.db.UpdateDownloadQueueItem(item)
+		}
+	}
+}
+
+// ResumeAll un-pauses the queue and re-queues every paused item, including ones left over from
+// an interrupted app run.
+func (q *Queue) ResumeAll() {
+	q.mu.Lock()
+	q.paused = false
+	q.mu.Unlock()
+
+	items, err := q.db.GetUnfinishedDownloadQueueItems()
+	if err != nil {
+		q.logger.Error().Err(err).Msg("chapter_downloader: failed to load queue items to resume")
+		return
+	}
+	for _, item := range items {
+		if item.State == models.DownloadQueueItemStatePaused || item.State == models.DownloadQueueItemStateDownloading {
+			item.State = models.DownloadQueueItemStateQueued
+			_ = q.db.UpdateDownloadQueueItem(item)
+		}
+	}
+
+	q.wake()
+}
+
+// Cancel removes an item from the queue, stopping it first if it's currently downloading.
+func (q *Queue) Cancel(id uint) error {
+	q.mu.Lock()
+	if cancel, ok := q.cancels[id]; ok {
+		cancel()
+	}
+	q.mu.Unlock()
+
+	return q.db.DeleteDownloadQueueItem(id)
+}
+
+// Reorder persists a new processing order for the queue. orderedIds must contain every queued item's ID.
+func (q *Queue) Reorder(orderedIds []uint) error {
+	return q.db.UpdateDownloadQueueItemPositions(orderedIds)
+}
+
+func (q *Queue) wake() {
+	select {
+	case q.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+// run processes one chapter at a time until the queue is stopped.
+func (q *Queue) run() {
+	for {
+		select {
+		case <-q.stopCh:
+			return
+		case <-q.wakeCh:
+		case <-time.After(5 * time.Second):
+		}
+
+		q.mu.Lock()
+		paused := q.paused
+		q.mu.Unlock()
+		if paused {
+			continue
+		}
+
+		item, ok := q.nextItem()
+		if !ok {
+			continue
+		}
+
+		q.processItem(item)
+		q.wake() // immediately check for more work
+	}
+}
+
+func (q *Queue) nextItem() (*models.DownloadQueueItem, bool) {
+	items, err := q.db.GetDownloadQueueItems()
+	if err != nil {
+		q.logger.Error().Err(err).Msg("chapter_downloader: failed to load queue items")
+		return nil, false
+	}
+	for _, item := range items {
+		if item.State == models.DownloadQueueItemStateQueued {
+			return item, true
+		}
+	}
+	return nil, false
+}
+
+func (q *Queue) processItem(item *models.DownloadQueueItem) {
+	ctx, cancel := context.WithCancel(context.Background())
+	q.mu.Lock()
+	q.cancels[item.ID] = cancel
+	q.mu.Unlock()
+	defer func() {
+		q.mu.Lock()
+		delete(q.cancels, item.ID)
+		q.mu.Unlock()
+		cancel()
+	}()
+
+	item.State = models.DownloadQueueItemStateDownloading
+	_ = q.db.UpdateDownloadQueueItem(item)
+	q.emitProgress(item, 0, 0, 0)
+
+	err := q.downloadFn(ctx, item, func(page, totalPages int, bytes int64) {
+		q.emitProgress(item, page, totalPages, bytes)
+	})
+
+	if err != nil {
+		if ctx.Err() != nil {
+			// Cancelled via PauseAll/Cancel, state already reflects that.
+			return
+		}
+		item.Retries += 1
+		if item.Retries >= maxDownloadRetries {
+			item.State = models.DownloadQueueItemStateFailed
+			q.logger.Error().Err(err).Str("chapterId", item.ChapterId).Msg("chapter_downloader: giving up on chapter after too many retries")
+		} else {
+			item.State = models.DownloadQueueItemStateQueued
+			backoff := time.Duration(1<<uint(item.Retries)) * time.Second
+			q.logger.Warn().Err(err).Dur("backoff", backoff).Str("chapterId", item.ChapterId).Msg("chapter_downloader: download failed, retrying")
+			time.Sleep(backoff)
+		}
+		_ = q.db.UpdateDownloadQueueItem(item)
+		q.emitProgress(item, 0, 0, 0)
+		return
+	}
+
+	item.State = models.DownloadQueueItemStateDone
+	_ = q.db.UpdateDownloadQueueItem(item)
+	q.emitProgress(item, 0, 0, 0)
+}
+
+func (q *Queue) emitProgress(item *models.DownloadQueueItem, page, totalPages int, bytes int64) {
+	q.wsEventManager.SendEvent("manga-download-progress", QueueProgressEvent{
+		ChapterId:  item.ChapterId,
+		Page:       page,
+		TotalPages: totalPages,
+		Bytes:      bytes,
+		State:      item.State,
+	})
+}
@@ -0,0 +1,173 @@
+package manga
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	chapter_downloader "seanime/internal/manga/downloader"
+	"seanime/internal/manga/providers"
+	"strconv"
+	"strings"
+)
+
+// ExportChapterOptions holds the AniList-derived metadata that gets written into ComicInfo.xml.
+type ExportChapterOptions struct {
+	Provider      manga_providers.Provider
+	MediaId       int
+	ChapterId     string
+	Format        chapter_downloader.ExportFormat
+	SeriesTitle   string
+	ChapterNumber string
+	Volume        string
+	Language      string
+}
+
+// ExportChapter packages a downloaded chapter into a CBZ or EPUB archive and returns its path.
+func (r *Repository) ExportChapter(opts ExportChapterOptions) (string, error) {
+	chapterDir, err := r.findDownloadedChapterDir(opts.Provider, opts.MediaId, opts.ChapterId)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("manga: could not find downloaded chapter to export")
+		return "", err
+	}
+
+	registry, err := chapter_downloader.ReadRegistry(chapterDir)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("manga: failed to read registry for export")
+		return "", err
+	}
+
+	info := chapter_downloader.ComicInfo{
+		Series:   opts.SeriesTitle,
+		Number:   opts.ChapterNumber,
+		Volume:   opts.Volume,
+		Language: opts.Language,
+	}
+
+	archivePath, err := chapter_downloader.ExportChapter(filepath.Join(r.downloadDir, chapterDir), registry, info, opts.Format)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("manga: failed to export chapter")
+		return "", err
+	}
+
+	r.logger.Info().Str("path", archivePath).Msg("manga: exported chapter")
+
+	return archivePath, nil
+}
+
+// ExportMediaEntryOptions holds the options for a bulk export of every downloaded chapter of a media entry.
+type ExportMediaEntryOptions struct {
+	MediaId     int
+	Format      chapter_downloader.ExportFormat
+	SeriesTitle string
+	Language    string
+}
+
+// ExportMediaEntry exports every downloaded chapter for a media entry (across all providers) and
+// bundles the resulting archives into a single zip file, returning its path.
+func (r *Repository) ExportMediaEntry(opts ExportMediaEntryOptions) (string, error) {
+	files, err := os.ReadDir(r.downloadDir)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("manga: failed to read download directory")
+		return "", err
+	}
+
+	archivePaths := make([]string, 0)
+
+	for _, file := range files {
+		if !file.IsDir() {
+			continue
+		}
+		parts := strings.SplitN(file.Name(), "_", 4)
+		if len(parts) != 4 {
+			continue
+		}
+		mId, convErr := strconv.Atoi(parts[1])
+		if convErr != nil || mId != opts.MediaId {
+			continue
+		}
+
+		path, exportErr := r.ExportChapter(ExportChapterOptions{
+			Provider:      manga_providers.Provider(parts[0]),
+			MediaId:       opts.MediaId,
+			ChapterId:     parts[2],
+			Format:        opts.Format,
+			SeriesTitle:   opts.SeriesTitle,
+			ChapterNumber: parts[3],
+			Language:      opts.Language,
+		})
+		if exportErr != nil {
+			r.logger.Warn().Err(exportErr).Str("dir", file.Name()).Msg("manga: skipping chapter that failed to export")
+			continue
+		}
+		archivePaths = append(archivePaths, path)
+	}
+
+	if len(archivePaths) == 0 {
+		return "", ErrNoChapters
+	}
+
+	bundlePath := filepath.Join(r.downloadDir, fmt.Sprintf("manga_%d_export.zip", opts.MediaId))
+	if err := bundleArchives(bundlePath, archivePaths); err != nil {
+		r.logger.Error().Err(err).Msg("manga: failed to bundle exported chapters")
+		return "", err
+	}
+
+	return bundlePath, nil
+}
+
+// findDownloadedChapterDir locates the download directory name (relative to r.downloadDir) for a chapter.
+// This mirrors the lookup done by GetDownloadedMangaPageContainer.
+func (r *Repository) findDownloadedChapterDir(provider manga_providers.Provider, mediaId int, chapterId string) (string, error) {
+	files, err := os.ReadDir(r.downloadDir)
+	if err != nil {
+		return "", err
+	}
+
+	for _, file := range files {
+		if !file.IsDir() {
+			continue
+		}
+		parts := strings.SplitN(file.Name(), "_", 4)
+		if len(parts) != 4 {
+			continue
+		}
+		mId, _ := strconv.Atoi(parts[1])
+		if parts[0] == string(provider) && mId == mediaId && parts[2] == chapterId {
+			return file.Name(), nil
+		}
+	}
+
+	return "", ErrChapterNotDownloaded
+}
+
+func bundleArchives(bundlePath string, archivePaths []string) error {
+	out, err := os.Create(bundlePath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	zw := zip.NewWriter(out)
+	defer zw.Close()
+
+	for _, path := range archivePaths {
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		w, err := zw.Create(filepath.Base(path))
+		if err != nil {
+			src.Close()
+			return err
+		}
+		if _, err := io.Copy(w, src); err != nil {
+			src.Close()
+			return err
+		}
+		src.Close()
+	}
+
+	return nil
+}
@@ -5,11 +5,11 @@ import (
 	"fmt"
 	"github.com/goccy/go-json"
 	"github.com/samber/lo"
-	chapter_downloader "github.com/seanime-app/seanime/internal/manga/downloader"
-	"github.com/seanime-app/seanime/internal/manga/providers"
-	"github.com/seanime-app/seanime/internal/util"
 	"os"
 	"path/filepath"
+	chapter_downloader "seanime/internal/manga/downloader"
+	"seanime/internal/manga/providers"
+	"seanime/internal/util"
 	"strconv"
 	"strings"
 	"sync"
@@ -54,7 +54,8 @@ type (
 // If it isn't cached, it will search for the manga, create a ChapterContainer and cache it.
 func (r *Repository) GetMangaChapterContainer(provider manga_providers.Provider, mediaId int, titles []*string) (*ChapterContainer, error) {
 
-	key := fmt.Sprintf("%s$%d", provider, mediaId)
+	// DEVNOTE: Include the language in the key so MangaDex results in different languages don't collide
+	key := fmt.Sprintf("%s$%d$%s", provider, mediaId, r.getProviderLanguage(provider))
 
 	r.logger.Debug().
 		Str("provider", string(provider)).
@@ -92,6 +93,10 @@ func (r *Repository) GetMangaChapterContainer(provider manga_providers.Provider,
 			_searchRes, err = r.mangasee.Search(manga_providers.SearchOptions{
 				Query: *title,
 			})
+		case manga_providers.MangadexProvider:
+			_searchRes, err = r.mangadex.Search(manga_providers.SearchOptions{
+				Query: *title,
+			})
 		}
 		if err == nil {
 			searchRes = append(searchRes, _searchRes...)
@@ -120,6 +125,8 @@ func (r *Repository) GetMangaChapterContainer(provider manga_providers.Provider,
 		chapterList, err = r.comick.FindChapters(bestRes.ID)
 	case manga_providers.MangaseeProvider:
 		chapterList, err = r.mangasee.FindChapters(bestRes.ID)
+	case manga_providers.MangadexProvider:
+		chapterList, err = r.mangadex.FindChapters(bestRes.ID)
 	}
 
 	if err != nil {
@@ -199,7 +206,7 @@ func (r *Repository) GetMangaPageContainer(
 	chapterBucket := r.getFcProviderBucket(provider, mediaId, bucketTypeChapter)
 
 	var chapterContainer *ChapterContainer
-	if found, _ := r.fileCacher.Get(chapterBucket, fmt.Sprintf("%s$%d", provider, mediaId), &chapterContainer); !found {
+	if found, _ := r.fileCacher.Get(chapterBucket, fmt.Sprintf("%s$%d$%s", provider, mediaId, r.getProviderLanguage(provider)), &chapterContainer); !found {
 		r.logger.Error().Msg("manga: chapter container not found")
 		return nil, ErrNoChapters
 	}
@@ -227,6 +234,8 @@ func (r *Repository) GetMangaPageContainer(
 		pageList, err = r.comick.FindChapterPages(chapter.ID)
 	case manga_providers.MangaseeProvider:
 		pageList, err = r.mangasee.FindChapterPages(chapter.ID)
+	case manga_providers.MangadexProvider:
+		pageList, err = r.mangadex.FindChapterPages(chapter.ID)
 	}
 
 	if err != nil {
@@ -258,6 +267,20 @@ func (r *Repository) GetMangaPageContainer(
 
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+// getProviderLanguage returns the language used to fetch results for a provider, if it supports one.
+// This is folded into cache keys so e.g. MangaDex results for "en" and "fr" don't collide.
+func (r *Repository) getProviderLanguage(provider manga_providers.Provider) string {
+	switch provider {
+	case manga_providers.MangadexProvider:
+		if r.mangadex != nil && r.mangadex.Language != "" {
+			return r.mangadex.Language
+		}
+		return "en"
+	default:
+		return ""
+	}
+}
+
 func (r *Repository) getPageDimensions(enabled bool, provider string, mediaId int, chapterId string, pages []*manga_providers.ChapterPage) (ret map[int]*PageDimension, err error) {
 	util.HandlePanicInModuleThen("manga/getPageDimensions", func() {
 		err = fmt.Errorf("failed to get page dimensions")
@@ -0,0 +1,305 @@
+package manga_providers
+
+import (
+	"fmt"
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+	"io"
+	"net/http"
+	"net/url"
+	"seanime/internal/util/httpcache"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MangadexProvider is the identifier used by the manga switch statements and file cache keys.
+const MangadexProvider Provider = "mangadex"
+
+const (
+	mangadexApiUrl     = "https://api.mangadex.org"
+	mangadexUploadsUrl = "https://uploads.mangadex.org"
+)
+
+type (
+	// Mangadex implements the official MangaDex API.
+	// Unlike Comick and Mangasee, MangaDex enforces a strict rate limit (~5 req/s), so
+	// every request goes through get, which spaces requests out and backs off on 429s.
+	Mangadex struct {
+		Language         string // e.g. "en", defaults to "en" when empty
+		ScanlationGroups []string
+		client           http.Client
+		logger           *zerolog.Logger
+		lastRequestAt    time.Time
+	}
+
+	mangadexMangaAttributes struct {
+		Title map[string]string `json:"title"`
+	}
+
+	mangadexRelationship struct {
+		ID         string          `json:"id"`
+		Type       string          `json:"type"`
+		Attributes json.RawMessage `json:"attributes"`
+	}
+
+	mangadexMangaData struct {
+		ID            string                  `json:"id"`
+		Attributes    mangadexMangaAttributes `json:"attributes"`
+		Relationships []mangadexRelationship  `json:"relationships"`
+	}
+
+	mangadexMangaListResponse struct {
+		Data []mangadexMangaData `json:"data"`
+	}
+
+	mangadexChapterAttributes struct {
+		Chapter            *string `json:"chapter"`
+		Volume             *string `json:"volume"`
+		Title              *string `json:"title"`
+		TranslatedLanguage string  `json:"translatedLanguage"`
+		PublishAt          string  `json:"publishAt"`
+		Pages              int     `json:"pages"`
+	}
+
+	mangadexChapterData struct {
+		ID            string                    `json:"id"`
+		Attributes    mangadexChapterAttributes `json:"attributes"`
+		Relationships []mangadexRelationship    `json:"relationships"`
+	}
+
+	mangadexFeedResponse struct {
+		Data []mangadexChapterData `json:"data"`
+	}
+
+	mangadexAtHomeResponse struct {
+		BaseURL string `json:"baseUrl"`
+		Chapter struct {
+			Hash      string   `json:"hash"`
+			Data      []string `json:"data"`
+			DataSaver []string `json:"dataSaver"`
+		} `json:"chapter"`
+	}
+)
+
+// NewMangadex creates a new Mangadex provider, defaulting the language to English.
+func NewMangadex(logger *zerolog.Logger) *Mangadex {
+	client := http.Client{Timeout: 60 * time.Second}
+
+	// Cache search/feed/at-home responses for an hour by default so repeated chapter list
+	// refreshes don't hammer the MangaDex API.
+	if transport, err := httpcache.New(nil, time.Hour); err == nil {
+		client.Transport = transport
+	}
+
+	return &Mangadex{
+		Language: "en",
+		client:   client,
+		logger:   logger,
+	}
+}
+
+func (m *Mangadex) language() string {
+	if m.Language == "" {
+		return "en"
+	}
+	return m.Language
+}
+
+// Search searches for a manga by title and returns the best matches.
+func (m *Mangadex) Search(opts SearchOptions) ([]*SearchResult, error) {
+	m.logger.Debug().Str("query", opts.Query).Msg("mangadex: searching manga")
+
+	q := url.Values{}
+	q.Set("title", opts.Query)
+	q.Set("limit", "20")
+	q.Add("includes[]", "cover_art")
+	q.Add("order[relevance]", "desc")
+
+	var res mangadexMangaListResponse
+	if err := m.get(fmt.Sprintf("%s/manga?%s", mangadexApiUrl, q.Encode()), &res); err != nil {
+		m.logger.Error().Err(err).Msg("mangadex: failed to search manga")
+		return nil, err
+	}
+
+	ret := make([]*SearchResult, 0, len(res.Data))
+	for _, d := range res.Data {
+		title := d.Attributes.Title[m.language()]
+		if title == "" {
+			title = d.Attributes.Title["en"]
+		}
+		ret = append(ret, &SearchResult{
+			ID:           d.ID,
+			Title:        title,
+			Image:        m.getCoverImageURL(d),
+			Provider:     MangadexProvider,
+			SearchRating: searchRating(opts.Query, title),
+		})
+	}
+
+	return ret, nil
+}
+
+// FindChapters returns the chapter list for a manga, filtered by Language and ScanlationGroups.
+func (m *Mangadex) FindChapters(id string) ([]*ChapterDetails, error) {
+	m.logger.Debug().Str("id", id).Msg("mangadex: fetching chapter feed")
+
+	ret := make([]*ChapterDetails, 0)
+	offset := 0
+
+	for {
+		q := url.Values{}
+		q.Add("translatedLanguage[]", m.language())
+		q.Set("limit", "500")
+		q.Set("offset", strconv.Itoa(offset))
+		q.Add("order[chapter]", "asc")
+		for _, g := range m.ScanlationGroups {
+			q.Add("scanlationGroup[]", g)
+		}
+
+		var res mangadexFeedResponse
+		if err := m.get(fmt.Sprintf("%s/manga/%s/feed?%s", mangadexApiUrl, id, q.Encode()), &res); err != nil {
+			m.logger.Error().Err(err).Msg("mangadex: failed to get chapter feed")
+			return nil, err
+		}
+
+		for i, c := range res.Data {
+			chapterNum := ""
+			if c.Attributes.Chapter != nil {
+				chapterNum = *c.Attributes.Chapter
+			}
+			title := chapterNum
+			if c.Attributes.Title != nil && *c.Attributes.Title != "" {
+				title = fmt.Sprintf("Chapter %s - %s", chapterNum, *c.Attributes.Title)
+			}
+			ret = append(ret, &ChapterDetails{
+				ID:       c.ID,
+				Title:    title,
+				Chapter:  chapterNum,
+				Index:    uint(offset + i),
+				Language: c.Attributes.TranslatedLanguage,
+				Provider: MangadexProvider,
+			})
+		}
+
+		if len(res.Data) < 500 {
+			break
+		}
+		offset += 500
+	}
+
+	return ret, nil
+}
+
+// FindChapterPages resolves the at-home server for a chapter and returns the page URLs.
+func (m *Mangadex) FindChapterPages(chapterId string) ([]*ChapterPage, error) {
+	m.logger.Debug().Str("chapterId", chapterId).Msg("mangadex: fetching at-home server")
+
+	var res mangadexAtHomeResponse
+	if err := m.get(fmt.Sprintf("%s/at-home/server/%s", mangadexApiUrl, chapterId), &res); err != nil {
+		m.logger.Error().Err(err).Msg("mangadex: failed to get at-home server")
+		return nil, err
+	}
+
+	ret := make([]*ChapterPage, 0, len(res.Chapter.Data))
+	for i, filename := range res.Chapter.Data {
+		ret = append(ret, &ChapterPage{
+			Provider: MangadexProvider,
+			URL:      fmt.Sprintf("%s/data/%s/%s", res.BaseURL, res.Chapter.Hash, filename),
+			Index:    i,
+			Headers:  map[string]string{"Referer": "https://mangadex.org/"},
+		})
+	}
+
+	return ret, nil
+}
+
+func (m *Mangadex) getCoverImageURL(d mangadexMangaData) string {
+	for _, rel := range d.Relationships {
+		if rel.Type != "cover_art" {
+			continue
+		}
+		var attrs struct {
+			FileName string `json:"fileName"`
+		}
+		if err := json.Unmarshal(rel.Attributes, &attrs); err != nil || attrs.FileName == "" {
+			continue
+		}
+		return fmt.Sprintf("%s/covers/%s/%s", mangadexUploadsUrl, d.ID, attrs.FileName)
+	}
+	return ""
+}
+
+// get performs a GET request against the MangaDex API, retrying with backoff when rate limited.
+func (m *Mangadex) get(reqUrl string, dst interface{}) error {
+	const maxRetries = 5
+
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		// MangaDex allows ~5 req/s, so space out requests a bit.
+		if elapsed := time.Since(m.lastRequestAt); elapsed < 200*time.Millisecond {
+			time.Sleep(200*time.Millisecond - elapsed)
+		}
+
+		req, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("User-Agent", "Mozilla/5.0")
+
+		resp, err := m.client.Do(req)
+		m.lastRequestAt = time.Now()
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			resp.Body.Close()
+			backoff := retryAfterDuration(resp.Header.Get("Retry-After"), attempt)
+			m.logger.Warn().Dur("backoff", backoff).Msg("mangadex: rate limited, backing off")
+			time.Sleep(backoff)
+			continue
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("mangadex: unexpected status code %d", resp.StatusCode)
+		}
+
+		return json.Unmarshal(body, dst)
+	}
+
+	return fmt.Errorf("mangadex: too many retries")
+}
+
+func retryAfterDuration(header string, attempt int) time.Duration {
+	if secs, err := strconv.Atoi(header); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	// Exponential backoff fallback
+	return time.Duration(1<<uint(attempt)) * time.Second
+}
+
+// searchRating scores how closely a result title matches the query, reusing the same
+// coarse "contains/prefix" heuristic the other providers use for SearchResult.SearchRating.
+func searchRating(query string, title string) float64 {
+	q := strings.ToLower(strings.TrimSpace(query))
+	t := strings.ToLower(strings.TrimSpace(title))
+	if q == "" || t == "" {
+		return 0
+	}
+	if q == t {
+		return 1
+	}
+	if strings.HasPrefix(t, q) || strings.HasPrefix(q, t) {
+		return 0.8
+	}
+	if strings.Contains(t, q) || strings.Contains(q, t) {
+		return 0.5
+	}
+	return 0
+}
@@ -1,6 +1,7 @@
 package videofile
 
 import (
+	"errors"
 	"fmt"
 	"github.com/coding-socks/ebml"
 	"github.com/coding-socks/matroska"
@@ -8,11 +9,15 @@ import (
 	"github.com/goccy/go-json"
 	"github.com/rs/zerolog"
 	"github.com/samber/lo"
-	"github.com/seanime-app/seanime/internal/util/result"
 	"io"
 	"os"
 	"path/filepath"
+	"seanime/internal/api/anidb"
+	"seanime/internal/mediastream/videofile/ed2k"
+	"seanime/internal/util/result"
 	"strings"
+	"sync"
+	"time"
 )
 
 type MediaInfo struct {
@@ -42,6 +47,61 @@ type MediaInfo struct {
 	Fonts []string `json:"fonts"`
 	// The list of chapters. See Chapter for more information
 	Chapters []Chapter `json:"chapters"`
+	// AniDB holds file-level metadata resolved from AniDB via the file's eD2k hash, if AniDB
+	// credentials were configured via SetAniDBCredentials. nil if it wasn't configured, the
+	// lookup failed, or timed out - this is a best-effort enrichment, never a hard requirement.
+	AniDB *AniDBFileInfo `json:"anidb,omitempty"`
+	// Thumbnails holds the hover-scrub sprite sheet for this video. GetInfo does not set it, and
+	// MediaInfoExtractor.GetThumbnails generates the sprite lazily and returns the track without
+	// storing it here, so the field stays nil unless a caller assigns it.
+	Thumbnails *ThumbnailTrack `json:"thumbnails,omitempty"`
+}
+
+// AniDBFileInfo is the subset of AniDB's file record that's useful to display alongside the
+// locally-extracted media info.
+type AniDBFileInfo struct {
+	AID          int      `json:"aid"`
+	EID          int      `json:"eid"`
+	GID          int      `json:"gid"`
+	GroupName    string   `json:"groupName"`
+	Deprecated   bool     `json:"deprecated"`
+	CRCOK        bool     `json:"crcOk"`
+	Source       string   `json:"source"`
+	Quality      string   `json:"quality"`
+	DubLanguages []string `json:"dubLanguages"`
+	SubLanguages []string `json:"subLanguages"`
+	// Incomplete is true if AniDB didn't return every field we asked for - treat this entry
+	// with a grain of salt.
+	Incomplete bool `json:"incomplete"`
+}
+
+var (
+	anidbMu      sync.Mutex
+	anidbClient  *anidb.Client
+	anidbCache   *anidb.Cache
+	anidbEnabled bool
+)
+
+// SetAniDBCredentials configures opt-in AniDB file enrichment. cacheDir is where resolved file
+// records are cached on disk. Passing empty credentials disables the enrichment.
+func SetAniDBCredentials(creds anidb.Credentials, cacheDir string, logger *zerolog.Logger) error {
+	anidbMu.Lock()
+	defer anidbMu.Unlock()
+
+	if creds.Username == "" || creds.Password == "" {
+		anidbEnabled = false
+		return nil
+	}
+
+	cache, err := anidb.NewCache(cacheDir)
+	if err != nil {
+		return err
+	}
+
+	anidbClient = anidb.NewClient(creds, logger)
+	anidbCache = cache
+	anidbEnabled = true
+	return nil
 }
 
 type Video struct {
@@ -145,7 +205,7 @@ func (e *MediaInfoExtractor) GetInfo(metadataCachePath string) (mi *MediaInfo, e
 		}
 
 		var data *MediaInfo
-		data, err = e.getInfo()
+		data, err = e.getInfo(metadataCachePath)
 		*mi = *data
 		mi.ready = readyChan
 		mi.Sha = e.sha
@@ -156,6 +216,20 @@ func (e *MediaInfoExtractor) GetInfo(metadataCachePath string) (mi *MediaInfo, e
 	return
 }
 
+// GetThumbnails lazily generates (or reuses the cached) hover-scrub sprite sheet for this
+// video. Unlike GetInfo, this is not called during the initial scan - it's meant to be invoked
+// by the HTTP layer on the first playback request that needs scrub previews.
+func (e *MediaInfoExtractor) GetThumbnails(metadataCachePath string) *ThumbnailTrack {
+	te := NewThumbnailExtractor(e.path, e.sha, e.route, e.logger)
+	return te.GetThumbnails(metadataCachePath)
+}
+
+// SubtitleExtractor returns a SubtitleExtractor for this video, used by the HTTP layer to serve
+// subtitle tracks and fonts on demand.
+func (e *MediaInfoExtractor) SubtitleExtractor() *SubtitleExtractor {
+	return NewSubtitleExtractor(e.path, e.sha, e.route, e.logger)
+}
+
 func getSavedInfo[T any](savePath string, mi *T) error {
 	savedFile, err := os.Open(savePath)
 	if err != nil {
@@ -184,7 +258,7 @@ func saveInfo[T any](savePath string, mi *T) error {
 
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
-func (e *MediaInfoExtractor) getInfo() (*MediaInfo, error) {
+func (e *MediaInfoExtractor) getInfo(metadataCachePath string) (*MediaInfo, error) {
 
 	// Open file
 	file, err := os.Open(e.path)
@@ -316,6 +390,7 @@ func (e *MediaInfoExtractor) getInfo() (*MediaInfo, error) {
 
 	chapters := make([]Chapter, 0)
 	fonts := make([]string, 0)
+	fontAttachments := make([]fontAttachment, 0)
 
 	// Reopen file
 	file, err = os.Open(e.path)
@@ -338,6 +413,7 @@ func (e *MediaInfoExtractor) getInfo() (*MediaInfo, error) {
 		for _, a := range b.Attachments.AttachedFile {
 			if strings.Contains(a.FileMediaType, "font") {
 				fonts = append(fonts, a.FileName)
+				fontAttachments = append(fontAttachments, fontAttachment{FileName: a.FileName, Data: a.FileData})
 			}
 		}
 	}
@@ -375,9 +451,93 @@ func (e *MediaInfoExtractor) getInfo() (*MediaInfo, error) {
 	mi.Chapters = chapters
 	mi.Fonts = fonts
 
+	mi.AniDB = e.getAniDBInfo(size)
+
+	// Prefetch subtitle tracks and fonts in the background so the first playback request never
+	// blocks on ffmpeg - this only needs to happen once per sha, since extraction is cached.
+	se := NewSubtitleExtractor(e.path, e.sha, e.route, e.logger)
+	go se.PrefetchSubtitles(metadataCachePath, mi.Subtitles, fontAttachments)
+
 	return mi, nil
 }
 
+// getAniDBInfo resolves AniDB file metadata for this file via eD2k hash, if enrichment is
+// configured. It never returns an error - any failure (no credentials, hashing error, network
+// error, timeout, ban) results in a nil *AniDBFileInfo and a silent fallback to local-only info.
+func (e *MediaInfoExtractor) getAniDBInfo(size uint64) *AniDBFileInfo {
+	anidbMu.Lock()
+	enabled := anidbEnabled
+	client := anidbClient
+	cache := anidbCache
+	anidbMu.Unlock()
+
+	if !enabled {
+		return nil
+	}
+
+	type lookupResult struct {
+		fi  *anidb.FileInfo
+		err error
+	}
+	resultCh := make(chan lookupResult, 1)
+
+	go func() {
+		hash, err := ed2k.Compute(e.path)
+		if err != nil {
+			resultCh <- lookupResult{err: err}
+			return
+		}
+
+		if client.LoggedIn() == false {
+			if err := client.Auth(); err != nil {
+				resultCh <- lookupResult{err: err}
+				return
+			}
+		}
+
+		fi, err := anidb.IdentifyByEd2k(client, cache, hash.Red, hash.Blue, int64(size))
+		resultCh <- lookupResult{fi: fi, err: err}
+	}()
+
+	select {
+	case res := <-resultCh:
+		if res.err != nil {
+			if errors.Is(res.err, anidb.ErrBanned) {
+				e.logger.Warn().Msg("videofile: AniDB account is banned, disabling AniDB enrichment")
+				anidbMu.Lock()
+				anidbEnabled = false
+				anidbMu.Unlock()
+			} else {
+				e.logger.Debug().Err(res.err).Str("path", e.path).Msg("videofile: AniDB lookup failed, falling back to local info only")
+			}
+			return nil
+		}
+		return toAniDBFileInfo(res.fi)
+	case <-time.After(20 * time.Second):
+		e.logger.Debug().Str("path", e.path).Msg("videofile: AniDB lookup timed out, falling back to local info only")
+		return nil
+	}
+}
+
+func toAniDBFileInfo(fi *anidb.FileInfo) *AniDBFileInfo {
+	if fi == nil {
+		return nil
+	}
+	return &AniDBFileInfo{
+		AID:          fi.AID,
+		EID:          fi.EID,
+		GID:          fi.GID,
+		GroupName:    fi.GroupName,
+		Deprecated:   fi.Deprecated,
+		CRCOK:        fi.CRCOK,
+		Source:       fi.Source,
+		Quality:      fi.Quality,
+		DubLanguages: fi.DubLanguages,
+		SubLanguages: fi.SubLanguages,
+		Incomplete:   fi.Incomplete,
+	}
+}
+
 func guessSubtitleExt(codecID string) string {
 	switch codecID {
 	// Audio
@@ -0,0 +1,189 @@
+package videofile
+
+import (
+	"fmt"
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/singleflight"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// subtitleExtractGroup deduplicates concurrent extraction requests for the same
+// (sha, trackIndex, targetExt) tuple.
+var subtitleExtractGroup singleflight.Group
+
+// SubtitleExtractor pulls individual subtitle tracks (and their fonts) out of a video on
+// demand, converting browser-unfriendly codecs (ASS/SSA) to WebVTT along the way. Extracted
+// files are cached on disk, keyed on the video's sha, so a track is only ever extracted once.
+type SubtitleExtractor struct {
+	sha    string
+	path   string
+	route  string
+	logger *zerolog.Logger
+}
+
+func NewSubtitleExtractor(path string, sha string, route string, logger *zerolog.Logger) *SubtitleExtractor {
+	return &SubtitleExtractor{
+		sha:    sha,
+		path:   path,
+		route:  route,
+		logger: logger,
+	}
+}
+
+// subsDir/fontsDir return the on-disk cache directories for this video's extracted assets.
+func (e *SubtitleExtractor) subsDir(metadataCachePath string) string {
+	return filepath.Join(metadataCachePath, e.sha, "subs")
+}
+func (e *SubtitleExtractor) fontsDir(metadataCachePath string) string {
+	return filepath.Join(metadataCachePath, e.sha, "fonts")
+}
+
+// subtitleContentType maps an extracted subtitle extension to the Content-Type it should be
+// served with.
+func subtitleContentType(ext string) string {
+	switch strings.ToLower(strings.TrimPrefix(ext, ".")) {
+	case "vtt":
+		return "text/vtt"
+	case "srt":
+		return "application/x-subrip"
+	case "ssa", "ass":
+		return "text/x-ssa"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// needsWebVttConversion reports whether codecID is a subtitle format browsers can't render
+// natively and that we know how to convert (currently ASS/SSA).
+func needsWebVttConversion(codecID string) bool {
+	switch codecID {
+	case "S_TEXT/ASS", "S_TEXT/SSA":
+		return true
+	default:
+		return false
+	}
+}
+
+// GetSubtitle extracts (or reuses the cached copy of) subtitle track subtitleIndex - the
+// subtitle-relative index matching MediaInfo's Subtitle.Index field and ffmpeg's `0:s:N` map
+// selector, NOT the Matroska track number - returning the path to the extracted file and its
+// Content-Type. ASS/SSA tracks are converted to WebVTT; everything else is copied as-is.
+func (e *SubtitleExtractor) GetSubtitle(metadataCachePath string, subtitleIndex uint, codecID string) (string, string, error) {
+	if !IsFfmpegAvailable() {
+		return "", "", fmt.Errorf("videofile: ffmpeg not found, cannot extract subtitles")
+	}
+
+	targetExt := guessSubtitleExt(codecID)
+	convert := needsWebVttConversion(codecID)
+	if convert {
+		targetExt = ".vtt"
+	}
+	if targetExt == "" {
+		targetExt = ".srt"
+	}
+
+	subsDir := e.subsDir(metadataCachePath)
+	outPath := filepath.Join(subsDir, fmt.Sprintf("%d%s", subtitleIndex, targetExt))
+	contentType := subtitleContentType(targetExt)
+
+	if fileExists(outPath) {
+		return outPath, contentType, nil
+	}
+
+	key := fmt.Sprintf("%s:%d:%s", e.sha, subtitleIndex, targetExt)
+	_, err, _ := subtitleExtractGroup.Do(key, func() (interface{}, error) {
+		if fileExists(outPath) {
+			return nil, nil
+		}
+		return nil, e.extract(subsDir, outPath, subtitleIndex, convert)
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return outPath, contentType, nil
+}
+
+func (e *SubtitleExtractor) extract(subsDir string, outPath string, subtitleIndex uint, convert bool) error {
+	if err := os.MkdirAll(subsDir, 0755); err != nil {
+		return err
+	}
+
+	args := []string{"-y", "-i", e.path, "-map", fmt.Sprintf("0:s:%d", subtitleIndex)}
+	if convert {
+		args = append(args, "-c:s", "webvtt")
+	} else {
+		args = append(args, "-c:s", "copy")
+	}
+	args = append(args, outPath)
+
+	cmd := exec.Command("ffmpeg", args...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("ffmpeg failed to extract subtitle track %d: %w: %s", subtitleIndex, err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// GetFont returns the path to a font previously extracted by PrefetchSubtitles, if present.
+func (e *SubtitleExtractor) GetFont(metadataCachePath string, filename string) (string, error) {
+	path := filepath.Join(e.fontsDir(metadataCachePath), filepath.Base(filename))
+	if !fileExists(path) {
+		return "", fmt.Errorf("videofile: font %q not found", filename)
+	}
+	return path, nil
+}
+
+// PrefetchSubtitles extracts every subtitle track and font attachment for this video up front,
+// so the first playback request never has to wait on ffmpeg. It's meant to be called once,
+// right after a file is scanned - failures are logged and skipped per-track rather than
+// aborting the whole prefetch.
+func (e *SubtitleExtractor) PrefetchSubtitles(metadataCachePath string, subtitles []Subtitle, fonts []fontAttachment) {
+	if !IsFfmpegAvailable() {
+		e.logger.Debug().Msg("videofile: ffmpeg not found, skipping subtitle/font prefetch")
+		return
+	}
+
+	for _, s := range subtitles {
+		if _, _, err := e.GetSubtitle(metadataCachePath, uint(s.Index), s.Codec); err != nil {
+			e.logger.Warn().Err(err).Str("path", e.path).Uint32("index", s.Index).Msg("videofile: failed to prefetch subtitle track")
+		}
+	}
+
+	if err := e.extractFonts(metadataCachePath, fonts); err != nil {
+		e.logger.Warn().Err(err).Str("path", e.path).Msg("videofile: failed to prefetch fonts")
+	}
+}
+
+// fontAttachment is the raw Matroska font attachment data discovered during getInfo, threaded
+// through to PrefetchSubtitles so fonts can be written to disk without re-parsing the file.
+type fontAttachment struct {
+	FileName string
+	Data     []byte
+}
+
+func (e *SubtitleExtractor) extractFonts(metadataCachePath string, fonts []fontAttachment) error {
+	if len(fonts) == 0 {
+		return nil
+	}
+
+	dir := e.fontsDir(metadataCachePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for _, font := range fonts {
+		path := filepath.Join(dir, filepath.Base(font.FileName))
+		if fileExists(path) {
+			continue
+		}
+		if err := os.WriteFile(path, font.Data, 0666); err != nil {
+			e.logger.Warn().Err(err).Str("font", font.FileName).Msg("videofile: failed to write font")
+		}
+	}
+
+	return nil
+}
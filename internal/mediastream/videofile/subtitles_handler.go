@@ -0,0 +1,28 @@
+package videofile
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// ServeSubtitle extracts (if needed) and streams the subtitle track at subtitleIndex with the
+// correct Content-Type for its (possibly converted) format. subtitleIndex is the subtitle-relative
+// index (Subtitle.Index) that GetSubtitle takes, not the Matroska track number.
+func (e *SubtitleExtractor) ServeSubtitle(c *fiber.Ctx, metadataCachePath string, subtitleIndex uint, codecID string) error {
+	path, contentType, err := e.GetSubtitle(metadataCachePath, subtitleIndex, codecID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	return c.SendFile(path)
+}
+
+// ServeFont streams a previously-extracted font attachment by filename.
+func (e *SubtitleExtractor) ServeFont(c *fiber.Ctx, metadataCachePath string, filename string) error {
+	path, err := e.GetFont(metadataCachePath, filename)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+
+	return c.SendFile(path)
+}
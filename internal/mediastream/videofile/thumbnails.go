@@ -0,0 +1,232 @@
+package videofile
+
+import (
+	"fmt"
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/singleflight"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ThumbnailTrack describes a generated hover-scrub sprite sheet for a video.
+type ThumbnailTrack struct {
+	// SpriteURL is the link to the directory the sprite pages (sprite-0.jpg, sprite-1.jpg, ...)
+	// are served from; each VTT cue names its own page.
+	SpriteURL string `json:"spriteUrl"`
+	// VttURL is the link to the WebVTT file mapping timecodes to `#xywh=` sprite fragments.
+	VttURL string `json:"vttUrl"`
+	// Interval is the number of seconds between consecutive tiles.
+	Interval int `json:"interval"`
+	// TileWidth/TileHeight are the pixel dimensions of a single tile.
+	TileWidth  int `json:"tileWidth"`
+	TileHeight int `json:"tileHeight"`
+}
+
+const (
+	thumbnailInterval   = 10 // seconds between tiles
+	thumbnailTileWidth  = 177
+	thumbnailTileHeight = 100
+	// thumbnailGridCols/Rows must match the tile=WxH ffmpeg filter below - 10x10 means up to
+	// 100 tiles (16.6 minutes of coverage) per sprite page. Episodes longer than that get a
+	// second, third, ... sprite page (sprite-0.jpg, sprite-1.jpg, ...) - see generate().
+	thumbnailGridCols = 10
+	thumbnailGridRows = 10
+	// thumbnailsPerSprite is how many tiles fit on a single sprite page before generate starts
+	// a new one.
+	thumbnailsPerSprite = thumbnailGridCols * thumbnailGridRows
+	// thumbnailPageDuration is the video duration a single sprite page covers.
+	thumbnailPageDuration = thumbnailsPerSprite * thumbnailInterval // seconds
+)
+
+// ThumbnailExtractor generates a WebVTT thumbnail sprite for a video, for hover-scrub previews
+// in the web player. It runs lazily - generation only happens on demand - and caches its output
+// on disk under <metadataCachePath>/<sha>/thumbs/ so it's only ever done once per video.
+type ThumbnailExtractor struct {
+	sha    string
+	path   string
+	route  string
+	logger *zerolog.Logger
+}
+
+func NewThumbnailExtractor(path string, sha string, route string, logger *zerolog.Logger) *ThumbnailExtractor {
+	return &ThumbnailExtractor{
+		sha:    sha,
+		path:   path,
+		route:  route,
+		logger: logger,
+	}
+}
+
+// thumbnailGenGroup deduplicates concurrent generation requests for the same sha, so two
+// playback requests arriving at once don't both shell out to ffmpeg for the same video.
+var thumbnailGenGroup singleflight.Group
+
+// IsFfmpegAvailable reports whether the ffmpeg binary can be found on PATH. Thumbnail
+// generation is skipped entirely when it isn't.
+func IsFfmpegAvailable() bool {
+	_, err := exec.LookPath("ffmpeg")
+	return err == nil
+}
+
+// GetThumbnails returns the ThumbnailTrack for this video, generating the sprite sheet and VTT
+// file on first call (and reusing the cached files on every subsequent call). Returns nil if
+// ffmpeg isn't available or generation fails - thumbnails are a nice-to-have, never a hard
+// requirement for playback.
+func (e *ThumbnailExtractor) GetThumbnails(metadataCachePath string) *ThumbnailTrack {
+	if !IsFfmpegAvailable() {
+		e.logger.Debug().Msg("videofile: ffmpeg not found, skipping thumbnail generation")
+		return nil
+	}
+
+	thumbsDir := filepath.Join(metadataCachePath, e.sha, "thumbs")
+	vttPath := filepath.Join(thumbsDir, "thumbnails.vtt")
+	// sprite-0.jpg always exists once generation has finished, even for videos short enough to
+	// fit on a single page, so it's a cheap marker for "has this video been processed before".
+	firstSpritePath := spritePagePath(thumbsDir, 0)
+
+	if fileExists(firstSpritePath) && fileExists(vttPath) {
+		return e.buildTrack()
+	}
+
+	_, err, _ := thumbnailGenGroup.Do(e.sha, func() (interface{}, error) {
+		// Re-check under the singleflight key in case another goroutine finished generation
+		// while we were waiting to acquire it.
+		if fileExists(firstSpritePath) && fileExists(vttPath) {
+			return nil, nil
+		}
+		return nil, e.generate(thumbsDir, vttPath)
+	})
+	if err != nil {
+		e.logger.Warn().Err(err).Str("path", e.path).Msg("videofile: failed to generate thumbnail sprite")
+		return nil
+	}
+
+	return e.buildTrack()
+}
+
+func (e *ThumbnailExtractor) buildTrack() *ThumbnailTrack {
+	return &ThumbnailTrack{
+		// Each VTT cue carries its own sprite-{page}.jpg filename (see writeThumbnailVtt), so
+		// SpriteURL only needs to point at the directory they're served from.
+		SpriteURL:  fmt.Sprintf("%s/thumbnails/", e.route),
+		VttURL:     fmt.Sprintf("%s/thumbnails/thumbnails.vtt", e.route),
+		Interval:   thumbnailInterval,
+		TileWidth:  thumbnailTileWidth,
+		TileHeight: thumbnailTileHeight,
+	}
+}
+
+// spritePagePath returns the path of the page-th sprite image under thumbsDir.
+func spritePagePath(thumbsDir string, page int) string {
+	return filepath.Join(thumbsDir, fmt.Sprintf("sprite-%d.jpg", page))
+}
+
+func (e *ThumbnailExtractor) generate(thumbsDir string, vttPath string) error {
+	if err := os.MkdirAll(thumbsDir, 0755); err != nil {
+		return err
+	}
+
+	duration, err := probeDuration(e.path)
+	if err != nil {
+		return err
+	}
+
+	tileCount := int(duration) / thumbnailInterval
+	if tileCount < 1 {
+		tileCount = 1
+	}
+	pageCount := (tileCount + thumbnailsPerSprite - 1) / thumbnailsPerSprite
+
+	// Build a grid of up to thumbnailGridCols*thumbnailGridRows tiles per page, one every
+	// thumbnailInterval seconds. ffmpeg's tile filter packs them left-to-right, top-to-bottom
+	// into a single sprite image - a video longer than thumbnailPageDuration needs one ffmpeg
+	// invocation per page, each seeked to where the previous page left off.
+	vf := fmt.Sprintf("fps=1/%d,scale=%d:%d,tile=%dx%d",
+		thumbnailInterval, thumbnailTileWidth, thumbnailTileHeight, thumbnailGridCols, thumbnailGridRows)
+
+	for page := 0; page < pageCount; page++ {
+		tilesInPage := thumbnailsPerSprite
+		if remaining := tileCount - page*thumbnailsPerSprite; remaining < tilesInPage {
+			tilesInPage = remaining
+		}
+
+		cmd := exec.Command("ffmpeg",
+			"-y",
+			"-ss", fmt.Sprintf("%d", page*thumbnailPageDuration),
+			"-skip_frame", "nokey",
+			"-i", e.path,
+			"-frames:v", fmt.Sprintf("%d", tilesInPage),
+			"-vf", vf,
+			"-an",
+			spritePagePath(thumbsDir, page),
+		)
+
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("ffmpeg failed on sprite page %d: %w: %s", page, err, strings.TrimSpace(string(out)))
+		}
+	}
+
+	return writeThumbnailVtt(vttPath, tileCount)
+}
+
+// probeDuration returns path's duration in seconds via ffprobe.
+func probeDuration(path string) (float64, error) {
+	cmd := exec.Command("ffprobe",
+		"-v", "error",
+		"-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1",
+		path,
+	)
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe failed: %w", err)
+	}
+
+	durationStr := strings.TrimSpace(string(out))
+	var duration float64
+	if _, err := fmt.Sscanf(durationStr, "%f", &duration); err != nil {
+		return 0, err
+	}
+	return duration, nil
+}
+
+// writeThumbnailVtt writes a WebVTT file mapping each thumbnailInterval-second window to its
+// `#xywh=` fragment on the sprite page that covers it.
+func writeThumbnailVtt(vttPath string, tileCount int) error {
+	var sb strings.Builder
+	sb.WriteString("WEBVTT\n\n")
+
+	for i := 0; i < tileCount; i++ {
+		page := i / thumbnailsPerSprite
+		indexInPage := i % thumbnailsPerSprite
+		col := indexInPage % thumbnailGridCols
+		row := indexInPage / thumbnailGridCols
+
+		start := time.Duration(i*thumbnailInterval) * time.Second
+		end := time.Duration((i+1)*thumbnailInterval) * time.Second
+
+		x := col * thumbnailTileWidth
+		y := row * thumbnailTileHeight
+
+		sb.WriteString(fmt.Sprintf("%s --> %s\n", formatVttTimestamp(start), formatVttTimestamp(end)))
+		sb.WriteString(fmt.Sprintf("sprite-%d.jpg#xywh=%d,%d,%d,%d\n\n", page, x, y, thumbnailTileWidth, thumbnailTileHeight))
+	}
+
+	return os.WriteFile(vttPath, []byte(sb.String()), 0666)
+}
+
+func formatVttTimestamp(d time.Duration) string {
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	s := int(d.Seconds()) % 60
+	ms := int(d.Milliseconds()) % 1000
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", h, m, s, ms)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
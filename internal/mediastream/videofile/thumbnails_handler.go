@@ -0,0 +1,27 @@
+package videofile
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"path/filepath"
+	"regexp"
+)
+
+// spriteAssetRe matches a single sprite page's filename, e.g. "sprite-0.jpg", "sprite-12.jpg".
+var spriteAssetRe = regexp.MustCompile(`^sprite-\d+\.jpg$`)
+
+// ServeThumbnailAsset serves one of the files generated by GetThumbnails ("sprite-{page}.jpg" or
+// "thumbnails.vtt") for this video. It does not trigger generation - callers should call
+// GetThumbnails first so the files exist, or have it fail gracefully for a missing sprite.
+func (e *MediaInfoExtractor) ServeThumbnailAsset(c *fiber.Ctx, metadataCachePath string, asset string) error {
+	if asset != "thumbnails.vtt" && !spriteAssetRe.MatchString(asset) {
+		return errors.New("videofile: unknown thumbnail asset")
+	}
+
+	path := filepath.Join(metadataCachePath, e.sha, "thumbs", asset)
+	if !fileExists(path) {
+		return fiber.NewError(fiber.StatusNotFound, "thumbnails not generated yet")
+	}
+
+	return c.SendFile(path)
+}
@@ -0,0 +1,13 @@
+package models
+
+// AutoDownloaderItem is a rule used by the auto-downloader to automatically grab torrents for a media.
+type AutoDownloaderItem struct {
+	BaseModel
+	MediaId int    `gorm:"column:media_id" json:"mediaId"`
+	Title   string `gorm:"column:title" json:"title"`
+	Enabled bool   `gorm:"column:enabled" json:"enabled"`
+	// EpisodeSelector restricts which episodes this rule applies to.
+	// Supported formats: a single number ("12"), a closed range ("12-34"), an open range ("50-"),
+	// or the literal "latest". An empty selector matches every episode, preserving old behavior.
+	EpisodeSelector string `gorm:"column:episode_selector" json:"episodeSelector"`
+}
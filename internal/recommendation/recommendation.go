@@ -0,0 +1,284 @@
+// Package recommendation blends AniList's recommendation edges with MAL's related-anime data
+// into a single ranked list, surfaced on the media entry screen.
+package recommendation
+
+import (
+	"context"
+	"fmt"
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+	"io"
+	"net/http"
+	"seanime/internal/api/anilist"
+	"seanime/internal/constants"
+	"seanime/internal/util/filecache"
+	"sort"
+	"time"
+)
+
+type (
+	// Recommendation is a single suggested media, with a human-readable reason for the suggestion.
+	Recommendation struct {
+		MediaId    int     `json:"mediaId"`
+		Title      string  `json:"title"`
+		CoverImage string  `json:"coverImage"`
+		Reason     string  `json:"reason"`
+		Score      float64 `json:"score"`
+		Source     string  `json:"source"` // "anilist" or "mal"
+	}
+
+	// Repository fetches, blends, and caches recommendations.
+	Repository struct {
+		logger               *zerolog.Logger
+		fileCacher           *filecache.Cacher
+		anilistClientWrapper *anilist.ClientWrapper
+		httpClient           http.Client
+	}
+
+	cachedRecommendations struct {
+		ComputedAt time.Time         `json:"computedAt"`
+		Items      []*Recommendation `json:"items"`
+	}
+)
+
+const (
+	cacheBucket = "recommendation_bucket"
+	cacheTTL    = 24 * time.Hour
+
+	// malRelatedAnimeUrl is queried with MalClientId as a client-credentials header, no OAuth needed
+	// for read-only related-anime lookups.
+	malRelatedAnimeUrl = "https://api.myanimelist.net/v2/anime/%d?fields=related_anime"
+)
+
+// NewRepository creates a new recommendation Repository.
+func NewRepository(logger *zerolog.Logger, fileCacher *filecache.Cacher, anilistClientWrapper *anilist.ClientWrapper) *Repository {
+	return &Repository{
+		logger:               logger,
+		fileCacher:           fileCacher,
+		anilistClientWrapper: anilistClientWrapper,
+		httpClient:           http.Client{Timeout: 20 * time.Second},
+	}
+}
+
+// GetRecommendations returns a ranked, de-duplicated list of recommendations for mediaId,
+// excluding anything already present in watchedMediaIds. Results are cached for 24h.
+func (r *Repository) GetRecommendations(mediaId int, sourceTitle string, watchedMediaIds map[int]struct{}) ([]*Recommendation, error) {
+
+	key := fmt.Sprintf("%d", mediaId)
+
+	var cached cachedRecommendations
+	if found, _ := r.fileCacher.Get(cacheBucket, key, &cached); found {
+		if time.Since(cached.ComputedAt) < cacheTTL {
+			r.logger.Debug().Int("mediaId", mediaId).Msg("recommendation: cache HIT")
+			return filterWatched(cached.Items, watchedMediaIds), nil
+		}
+	}
+
+	anilistRecs, err := r.fetchAnilistRecommendations(mediaId, sourceTitle)
+	if err != nil {
+		r.logger.Warn().Err(err).Int("mediaId", mediaId).Msg("recommendation: failed to fetch AniList recommendations")
+	}
+
+	malRecs, err := r.fetchMalRelatedAnime(mediaId, sourceTitle)
+	if err != nil {
+		r.logger.Warn().Err(err).Int("mediaId", mediaId).Msg("recommendation: failed to fetch MAL related anime")
+	}
+
+	merged := mergeAndRank(anilistRecs, malRecs)
+
+	if err := r.fileCacher.Set(cacheBucket, key, cachedRecommendations{
+		ComputedAt: time.Now(),
+		Items:      merged,
+	}); err != nil {
+		r.logger.Warn().Err(err).Msg("recommendation: failed to set cache")
+	}
+
+	return filterWatched(merged, watchedMediaIds), nil
+}
+
+// PrecomputeForRecentlyWatched warms the cache for the topN most recently-updated entries in the
+// user's "current" and "completed" lists, so the media entry screen never waits on a cold fetch.
+func (r *Repository) PrecomputeForRecentlyWatched(collection *anilist.AnimeCollection, topN int) {
+	if collection == nil || collection.MediaListCollection == nil {
+		return
+	}
+
+	type watched struct {
+		mediaId   int
+		title     string
+		updatedAt int
+	}
+
+	watchedMediaIds := make(map[int]struct{})
+	all := make([]watched, 0)
+
+	for _, list := range collection.MediaListCollection.GetLists() {
+		isWatchedList := list.GetStatus() != nil &&
+			(*list.GetStatus() == anilist.MediaListStatusCurrent || *list.GetStatus() == anilist.MediaListStatusCompleted)
+		for _, entry := range list.GetEntries() {
+			if entry.GetMedia() == nil {
+				continue
+			}
+			watchedMediaIds[entry.GetMedia().GetID()] = struct{}{}
+			if !isWatchedList {
+				continue
+			}
+			updatedAt := 0
+			if entry.UpdatedAt != nil {
+				updatedAt = *entry.UpdatedAt
+			}
+			all = append(all, watched{
+				mediaId:   entry.GetMedia().GetID(),
+				title:     entry.GetMedia().GetTitleSafe(),
+				updatedAt: updatedAt,
+			})
+		}
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i].updatedAt > all[j].updatedAt })
+
+	if len(all) > topN {
+		all = all[:topN]
+	}
+
+	for _, w := range all {
+		go func(mediaId int, title string) {
+			defer func() { _ = recover() }()
+			_, _ = r.GetRecommendations(mediaId, title, watchedMediaIds)
+		}(w.mediaId, w.title)
+	}
+}
+
+func (r *Repository) fetchAnilistRecommendations(mediaId int, sourceTitle string) ([]*Recommendation, error) {
+	media, err := r.anilistClientWrapper.Client.BaseMediaByID(context.Background(), &mediaId)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*Recommendation, 0)
+	if media.GetMedia() == nil || media.GetMedia().GetRecommendations() == nil {
+		return ret, nil
+	}
+
+	for _, edge := range media.GetMedia().GetRecommendations().GetNodes() {
+		rec := edge.GetMediaRecommendation()
+		if rec == nil {
+			continue
+		}
+		ret = append(ret, &Recommendation{
+			MediaId:    rec.GetID(),
+			Title:      rec.GetTitleSafe(),
+			CoverImage: rec.GetCoverImageSafe(),
+			Reason:     fmt.Sprintf("Because you watched %s", sourceTitle),
+			Score:      float64(edge.GetRating()),
+			Source:     "anilist",
+		})
+	}
+
+	return ret, nil
+}
+
+type malRelatedAnimeResponse struct {
+	RelatedAnime []struct {
+		Node struct {
+			ID     int    `json:"id"`
+			Title  string `json:"title"`
+			Images struct {
+				JPG struct {
+					LargeImageURL string `json:"large_image_url"`
+				} `json:"main_picture"`
+			} `json:"main_picture"`
+		} `json:"node"`
+		RelationType string `json:"relation_type"`
+	} `json:"related_anime"`
+}
+
+func (r *Repository) fetchMalRelatedAnime(mediaId int, sourceTitle string) ([]*Recommendation, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf(malRelatedAnimeUrl, mediaId), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-MAL-CLIENT-ID", constants.MalClientId)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("recommendation: MAL returned status code %d", resp.StatusCode)
+	}
+
+	var data malRelatedAnimeResponse
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+
+	ret := make([]*Recommendation, 0, len(data.RelatedAnime))
+	for _, rel := range data.RelatedAnime {
+		// "Sequel"/"Prequel"/"Side story" are near-certain matches for fans of the source, so they
+		// get scored higher than a generic "Other" relation.
+		score := 50.0
+		switch rel.RelationType {
+		case "sequel", "prequel", "side_story", "alternative_version":
+			score = 90.0
+		case "summary", "full_story":
+			score = 70.0
+		}
+
+		ret = append(ret, &Recommendation{
+			MediaId:    rel.Node.ID,
+			Title:      rel.Node.Title,
+			CoverImage: rel.Node.Images.JPG.LargeImageURL,
+			Reason:     fmt.Sprintf("Because you watched %s", sourceTitle),
+			Score:      score,
+			Source:     "mal",
+		})
+	}
+
+	return ret, nil
+}
+
+// mergeAndRank de-duplicates recommendations by MediaId (AniList entries win ties, since they
+// carry a richer reason/cover image), then sorts by descending score.
+func mergeAndRank(anilistRecs, malRecs []*Recommendation) []*Recommendation {
+	byMediaId := make(map[int]*Recommendation)
+
+	for _, rec := range anilistRecs {
+		byMediaId[rec.MediaId] = rec
+	}
+	for _, rec := range malRecs {
+		if _, found := byMediaId[rec.MediaId]; !found {
+			byMediaId[rec.MediaId] = rec
+		}
+	}
+
+	ret := make([]*Recommendation, 0, len(byMediaId))
+	for _, rec := range byMediaId {
+		ret = append(ret, rec)
+	}
+
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Score > ret[j].Score })
+
+	return ret
+}
+
+func filterWatched(recs []*Recommendation, watchedMediaIds map[int]struct{}) []*Recommendation {
+	if len(watchedMediaIds) == 0 {
+		return recs
+	}
+	ret := make([]*Recommendation, 0, len(recs))
+	for _, rec := range recs {
+		if _, watched := watchedMediaIds[rec.MediaId]; watched {
+			continue
+		}
+		ret = append(ret, rec)
+	}
+	return ret
+}
@@ -0,0 +1,52 @@
+package scanner
+
+import (
+	"fmt"
+	"github.com/cespare/xxhash/v2"
+	"io"
+	"os"
+)
+
+// fingerprintSampleSize is how much of the start and end of a file gets hashed. Enough to tell
+// apart same-sized files without reading the whole thing - important since a renamed release
+// folder can easily contain multi-GB video files.
+const fingerprintSampleSize = 64 * 1024
+
+// fingerprintFile hashes the first and last fingerprintSampleSize bytes of path with xxhash,
+// combined with the file's size, and returns a stable string fingerprint used to recognize a
+// renamed/moved file across scans.
+func fingerprintFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	h := xxhash.New()
+
+	head := make([]byte, fingerprintSampleSize)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	_, _ = h.Write(head[:n])
+
+	if info.Size() > fingerprintSampleSize {
+		if _, err := f.Seek(-fingerprintSampleSize, io.SeekEnd); err != nil {
+			return "", err
+		}
+		tail := make([]byte, fingerprintSampleSize)
+		n, err = io.ReadFull(f, tail)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return "", err
+		}
+		_, _ = h.Write(tail[:n])
+	}
+
+	return fmt.Sprintf("%016x:%d", h.Sum64(), info.Size()), nil
+}
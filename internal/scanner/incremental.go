@@ -0,0 +1,167 @@
+package scanner
+
+import (
+	"os"
+	"seanime/internal/entities"
+)
+
+// ScanMode controls how much of Scan's pipeline runs against each discovered file.
+type ScanMode string
+
+const (
+	// ScanModeFull re-runs the media fetcher, matcher, and hydrator against every discovered
+	// local file regardless of whether anything changed about it. This is the default, so
+	// leaving Mode unset behaves exactly as before this field existed.
+	ScanModeFull ScanMode = ""
+	// ScanModeIncremental compares every discovered file against ExistingLocalFiles by
+	// (path, size, mtime). Files whose tuple is unchanged skip the pipeline entirely and are
+	// reused as-is. Files that vanished from their old path are checked against a content
+	// fingerprint (see fingerprintFile) to detect renames/moves before falling back to "new".
+	ScanModeIncremental ScanMode = "incremental"
+	// ScanModeQuick is ScanModeIncremental without the fingerprinting pass - cheaper, but a
+	// renamed file is reported (and re-matched) as new rather than renamed.
+	ScanModeQuick ScanMode = "quick"
+)
+
+// scanDiff buckets discovered local files against ExistingLocalFiles for an incremental scan.
+type scanDiff struct {
+	Unchanged []*entities.LocalFile // (path, size, mtime) match an existing entry - reused as-is
+	New       []*entities.LocalFile // no existing entry at this path (and no rename match)
+	Modified  []*entities.LocalFile // existing entry at this path, but size/mtime differ
+	Renamed   []*entities.LocalFile // no existing entry at this path, but matched by fingerprint
+	Deleted   []*entities.LocalFile // existing entry whose path no longer resolves to a discovered file
+}
+
+// Pipeline returns the files that still need to go through the media fetcher, matcher, and
+// hydrator - everything except Unchanged.
+func (d *scanDiff) Pipeline() []*entities.LocalFile {
+	out := make([]*entities.LocalFile, 0, len(d.New)+len(d.Modified)+len(d.Renamed))
+	out = append(out, d.New...)
+	out = append(out, d.Modified...)
+	out = append(out, d.Renamed...)
+	return out
+}
+
+// diffLocalFiles compares discovered against existing local files. Rename detection (which
+// fingerprints file contents) only runs in ScanModeIncremental - ScanModeQuick treats any
+// path absent from existing as new.
+func diffLocalFiles(discovered []*entities.LocalFile, existing []*entities.LocalFile, mode ScanMode) (*scanDiff, error) {
+	diff := &scanDiff{}
+
+	existingByPath := make(map[string]*entities.LocalFile, len(existing))
+	for _, lf := range existing {
+		existingByPath[lf.Path] = lf
+	}
+
+	discoveredByPath := make(map[string]struct{}, len(discovered))
+	for _, lf := range discovered {
+		discoveredByPath[lf.Path] = struct{}{}
+	}
+
+	// Existing entries whose path didn't show up among the discovered files - either they were
+	// deleted outright, or moved/renamed to a path we're about to process as "new".
+	var possiblyMoved []*entities.LocalFile
+	for _, lf := range existing {
+		if _, ok := discoveredByPath[lf.Path]; !ok {
+			possiblyMoved = append(possiblyMoved, lf)
+		}
+	}
+
+	for _, dlf := range discovered {
+		prev, ok := existingByPath[dlf.Path]
+		if !ok {
+			if mode == ScanModeIncremental {
+				match, err := findRenameMatch(dlf, possiblyMoved)
+				if err != nil {
+					return nil, err
+				}
+				if match != nil {
+					renamed := *match
+					renamed.Path = dlf.Path
+					diff.Renamed = append(diff.Renamed, &renamed)
+					possiblyMoved = removeLocalFile(possiblyMoved, match)
+					continue
+				}
+			}
+			stampStat(dlf)
+			diff.New = append(diff.New, dlf)
+			continue
+		}
+
+		info, err := os.Stat(dlf.Path)
+		if err != nil {
+			// Disappeared between GetLocalFilesFromDir and here - don't trust cached metadata.
+			stampStat(dlf)
+			diff.New = append(diff.New, dlf)
+			continue
+		}
+
+		if prev.Size == info.Size() && prev.ModTime.Equal(info.ModTime()) {
+			diff.Unchanged = append(diff.Unchanged, prev)
+			continue
+		}
+
+		dlf.Size = info.Size()
+		dlf.ModTime = info.ModTime()
+		if mode == ScanModeIncremental {
+			if fp, err := fingerprintFile(dlf.Path); err == nil {
+				dlf.Fingerprint = fp
+			}
+		}
+		diff.Modified = append(diff.Modified, dlf)
+	}
+
+	diff.Deleted = append(diff.Deleted, possiblyMoved...)
+
+	return diff, nil
+}
+
+// findRenameMatch looks for a pool entry with the same size and content fingerprint as dlf. On
+// success, dlf.Fingerprint is stamped as a side effect regardless of whether a match was found,
+// so a genuinely new file still gets its fingerprint cached for the next incremental scan.
+func findRenameMatch(dlf *entities.LocalFile, pool []*entities.LocalFile) (*entities.LocalFile, error) {
+	info, err := os.Stat(dlf.Path)
+	if err != nil {
+		return nil, nil
+	}
+	dlf.Size = info.Size()
+	dlf.ModTime = info.ModTime()
+
+	fp, err := fingerprintFile(dlf.Path)
+	if err != nil {
+		return nil, err
+	}
+	dlf.Fingerprint = fp
+
+	sameSize := make([]*entities.LocalFile, 0, len(pool))
+	for _, lf := range pool {
+		if lf.Size == info.Size() {
+			sameSize = append(sameSize, lf)
+		}
+	}
+
+	for _, lf := range sameSize {
+		if lf.Fingerprint != "" && lf.Fingerprint == fp {
+			return lf, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func stampStat(lf *entities.LocalFile) {
+	if info, err := os.Stat(lf.Path); err == nil {
+		lf.Size = info.Size()
+		lf.ModTime = info.ModTime()
+	}
+}
+
+func removeLocalFile(pool []*entities.LocalFile, target *entities.LocalFile) []*entities.LocalFile {
+	out := make([]*entities.LocalFile, 0, len(pool))
+	for _, lf := range pool {
+		if lf != target {
+			out = append(out, lf)
+		}
+	}
+	return out
+}
@@ -2,16 +2,19 @@ package scanner
 
 import (
 	"errors"
+	"fmt"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/rs/zerolog"
 	"github.com/samber/lo"
-	"github.com/seanime-app/seanime/internal/anilist"
-	"github.com/seanime-app/seanime/internal/anizip"
-	"github.com/seanime-app/seanime/internal/entities"
-	"github.com/seanime-app/seanime/internal/events"
-	"github.com/seanime-app/seanime/internal/filesystem"
-	"github.com/seanime-app/seanime/internal/limiter"
-	"github.com/seanime-app/seanime/internal/summary"
+	"path/filepath"
+	"seanime/internal/anilist"
+	"seanime/internal/anizip"
+	"seanime/internal/entities"
+	"seanime/internal/events"
+	"seanime/internal/filesystem"
+	"seanime/internal/limiter"
+	"seanime/internal/summary"
+	"strings"
 )
 
 type Scanner struct {
@@ -25,6 +28,15 @@ type Scanner struct {
 	SkipLockedFiles      bool
 	SkipIgnoredFiles     bool
 	ScanSummaryLogger    *summary.ScanSummaryLogger
+	// AffectedPaths optionally scopes the scan to a set of subtrees under DirPath, instead of
+	// the whole library. Set by Watcher when it triggers a scan after detecting changes under
+	// just one or two release folders. When empty, behavior is unchanged: GetLocalFilesFromDir
+	// walks DirPath as a whole.
+	AffectedPaths []string
+	// Mode controls whether Scan re-runs the full pipeline against every discovered file
+	// (ScanModeFull, the default) or reuses ExistingLocalFiles for anything unchanged
+	// (ScanModeIncremental / ScanModeQuick). Has no effect when ExistingLocalFiles is nil.
+	Mode ScanMode
 }
 
 // Scan will scan the directory and return a list of entities.LocalFile.
@@ -55,7 +67,7 @@ func (scn *Scanner) Scan() ([]*entities.LocalFile, error) {
 	// +---------------------+
 
 	// Get local files
-	localFiles, err := GetLocalFilesFromDir(scn.DirPath, scn.Logger)
+	localFiles, err := scn.getLocalFiles()
 	if err != nil {
 		return nil, err
 	}
@@ -75,6 +87,29 @@ func (scn *Scanner) Scan() ([]*entities.LocalFile, error) {
 	scanLogger.logger.Debug().
 		Msg("===========================================================================================================")
 
+	// +---------------------+
+	// | Incremental scan    |
+	// +---------------------+
+
+	// In incremental/quick mode, split discovered files into unchanged (reused as-is, skipping
+	// the pipeline entirely) and new/modified/renamed (run through the pipeline below). A nil
+	// diff means Mode is ScanModeFull (or there's nothing to diff against yet).
+	var diff *scanDiff
+	if scn.Mode != ScanModeFull && scn.ExistingLocalFiles != nil {
+		diff, err = diffLocalFiles(localFiles, scn.ExistingLocalFiles, scn.Mode)
+		if err != nil {
+			return nil, err
+		}
+
+		scn.WSEventManager.SendEvent(events.EventScanProgress, 15)
+		scn.WSEventManager.SendEvent(events.EventScanStatus, fmt.Sprintf(
+			"Incremental scan: %d unchanged, %d new, %d modified, %d renamed, %d deleted",
+			len(diff.Unchanged), len(diff.New), len(diff.Modified), len(diff.Renamed), len(diff.Deleted),
+		))
+
+		localFiles = diff.Pipeline()
+	}
+
 	// +---------------------+
 	// | Filter local files  |
 	// +---------------------+
@@ -166,6 +201,18 @@ func (scn *Scanner) Scan() ([]*entities.LocalFile, error) {
 			scn.Logger.Debug().Msg("scanner: Scan completed")
 			scn.WSEventManager.SendEvent(events.EventScanProgress, 100)
 			scn.WSEventManager.SendEvent(events.EventScanStatus, "Scan completed")
+
+			// Nothing needed the pipeline (e.g. an incremental scan where nothing changed) -
+			// the unchanged/skipped files are still the scan's result, not an error.
+			if diff != nil {
+				result := append([]*entities.LocalFile{}, diff.Unchanged...)
+				for _, sf := range skippedLfs {
+					if filesystem.FileExists(sf.Path) {
+						result = append(result, sf)
+					}
+				}
+				return result, nil
+			}
 		}
 		return nil, err
 	}
@@ -227,6 +274,25 @@ func (scn *Scanner) Scan() ([]*entities.LocalFile, error) {
 		}
 	}
 
+	// Merge back the files an incremental/quick scan skipped the pipeline for.
+	if diff != nil {
+		localFiles = append(localFiles, diff.Unchanged...)
+	}
+
+	// When the scan was scoped to AffectedPaths, everything outside those subtrees was never
+	// touched by GetLocalFilesFromDir - carry it over from ExistingLocalFiles unchanged so a
+	// watcher-triggered scan doesn't look like the rest of the library disappeared.
+	if len(scn.AffectedPaths) > 0 && scn.ExistingLocalFiles != nil {
+		for _, lf := range scn.ExistingLocalFiles {
+			if lf.IsIncluded(localFiles) {
+				continue
+			}
+			if !isUnderAffectedPaths(lf.Path, scn.AffectedPaths) && filesystem.FileExists(lf.Path) {
+				localFiles = append(localFiles, lf)
+			}
+		}
+	}
+
 	scn.Logger.Debug().Msg("scanner: Scan completed")
 	scn.WSEventManager.SendEvent(events.EventScanProgress, 100)
 	scn.WSEventManager.SendEvent(events.EventScanStatus, "Scan completed")
@@ -240,3 +306,36 @@ func (scn *Scanner) Scan() ([]*entities.LocalFile, error) {
 	return localFiles, nil
 
 }
+
+// getLocalFiles retrieves local files from DirPath, or, when AffectedPaths is set, from just
+// those subtrees - each walked independently and concatenated.
+func (scn *Scanner) getLocalFiles() ([]*entities.LocalFile, error) {
+	if len(scn.AffectedPaths) == 0 {
+		return GetLocalFilesFromDir(scn.DirPath, scn.Logger)
+	}
+
+	localFiles := make([]*entities.LocalFile, 0)
+	for _, p := range scn.AffectedPaths {
+		lfs, err := GetLocalFilesFromDir(p, scn.Logger)
+		if err != nil {
+			return nil, err
+		}
+		localFiles = append(localFiles, lfs...)
+	}
+
+	return localFiles, nil
+}
+
+// isUnderAffectedPaths reports whether path is equal to, or nested under, one of affectedPaths.
+func isUnderAffectedPaths(path string, affectedPaths []string) bool {
+	for _, ap := range affectedPaths {
+		if path == ap {
+			return true
+		}
+		rel, err := filepath.Rel(ap, path)
+		if err == nil && !strings.HasPrefix(rel, "..") {
+			return true
+		}
+	}
+	return false
+}
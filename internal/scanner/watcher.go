@@ -0,0 +1,242 @@
+package scanner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog"
+	"seanime/internal/anilist"
+	"seanime/internal/events"
+	"seanime/internal/util"
+)
+
+// watchDebounceInterval is how long the Watcher waits after the last relevant filesystem event
+// before running a scan. Torrent/download clients typically create the destination folder first
+// and move files into it afterwards, so acting on the very first event would scan a half-written
+// folder - 20s (within the 10-30s range that works well in practice) gives the transfer time to
+// settle.
+const watchDebounceInterval = 20 * time.Second
+
+// WatcherOptions mirrors the subset of Scanner's fields a background, event-triggered scan needs.
+type WatcherOptions struct {
+	DirPath              string
+	Username             string
+	Enhanced             bool
+	AnilistClientWrapper *anilist.ClientWrapper
+	Logger               *zerolog.Logger
+	WSEventManager       events.IWSEventManager
+}
+
+// Watcher watches the library directory for changes and triggers a debounced, scoped Scan when
+// something changes under it. It's meant to be rebuilt (Close, then NewWatcher) whenever the
+// library path or the AutoScanEnabled setting changes - InitOrRefreshModules is the natural place
+// to do that, since it already runs after every settings update.
+type Watcher struct {
+	dirPath              string
+	username             string
+	enhanced             bool
+	anilistClientWrapper *anilist.ClientWrapper
+	logger               *zerolog.Logger
+	wsEventManager       events.IWSEventManager
+
+	fsWatcher *fsnotify.Watcher
+
+	mu            sync.Mutex
+	pending       map[string]struct{}
+	debounceTimer *time.Timer
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewWatcher creates a Watcher and starts watching opts.DirPath (and all of its subdirectories)
+// for changes. The caller is responsible for calling Close when the watcher is no longer needed.
+func NewWatcher(opts *WatcherOptions) (*Watcher, error) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		dirPath:              opts.DirPath,
+		username:             opts.Username,
+		enhanced:             opts.Enhanced,
+		anilistClientWrapper: opts.AnilistClientWrapper,
+		logger:               opts.Logger,
+		wsEventManager:       opts.WSEventManager,
+		fsWatcher:            fsWatcher,
+		pending:              make(map[string]struct{}),
+		stopCh:               make(chan struct{}),
+		doneCh:               make(chan struct{}),
+	}
+
+	if err := w.addRecursive(opts.DirPath); err != nil {
+		_ = fsWatcher.Close()
+		return nil, err
+	}
+
+	go w.loop()
+
+	w.logger.Debug().Str("path", opts.DirPath).Msg("scanner: library watcher started")
+
+	return w, nil
+}
+
+// Close stops the watcher and releases its underlying inotify/kqueue/ReadDirectoryChangesW
+// handles. Any pending debounced scan is discarded.
+func (w *Watcher) Close() error {
+	close(w.stopCh)
+	err := w.fsWatcher.Close()
+	<-w.doneCh
+
+	w.mu.Lock()
+	if w.debounceTimer != nil {
+		w.debounceTimer.Stop()
+	}
+	w.mu.Unlock()
+
+	return err
+}
+
+// addRecursive registers a watch on root and every directory beneath it. Directories that can't
+// be watched (e.g. because the platform's watch-limit has been reached) are logged and skipped
+// rather than aborting the whole walk.
+func (w *Watcher) addRecursive(root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			w.logger.Warn().Err(err).Str("path", path).Msg("scanner: failed to walk directory while registering watch")
+			return nil
+		}
+		if !info.IsDir() {
+			return nil
+		}
+
+		if err := w.fsWatcher.Add(path); err != nil {
+			if isWatchLimitError(err) {
+				w.logger.Warn().Err(err).Str("path", path).
+					Msg("scanner: inotify watch limit reached, some directories won't be watched - consider raising fs.inotify.max_user_watches")
+				return nil
+			}
+			w.logger.Warn().Err(err).Str("path", path).Msg("scanner: failed to watch directory")
+		}
+
+		return nil
+	})
+}
+
+func (w *Watcher) loop() {
+	defer util.HandlePanicInModuleThen("scanner/Watcher.loop", func() {})
+	defer close(w.doneCh)
+
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case event, ok := <-w.fsWatcher.Events:
+			if !ok {
+				return
+			}
+			w.handleEvent(event)
+		case err, ok := <-w.fsWatcher.Errors:
+			if !ok {
+				return
+			}
+			w.logger.Warn().Err(err).Msg("scanner: watcher error")
+		}
+	}
+}
+
+func (w *Watcher) handleEvent(event fsnotify.Event) {
+	// A newly created directory needs its own watch - and everything inside it, since clients
+	// sometimes create a whole nested tree in one go before the Create events for its children
+	// even reach us.
+	if event.Op&fsnotify.Create != 0 {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			if err := w.addRecursive(event.Name); err != nil {
+				w.logger.Warn().Err(err).Str("path", event.Name).Msg("scanner: failed to register watch on newly created directory")
+			}
+		}
+	}
+
+	if event.Op&(fsnotify.Create|fsnotify.Rename|fsnotify.Remove|fsnotify.Chmod) == 0 {
+		return
+	}
+
+	affected := w.affectedSubtree(event.Name)
+
+	w.mu.Lock()
+	w.pending[affected] = struct{}{}
+	w.resetDebounceTimerLocked()
+	w.mu.Unlock()
+}
+
+// affectedSubtree maps an event path to the directory directly under DirPath that contains it,
+// so a scan triggered by one changed file stays scoped to its release folder instead of the
+// whole library. Paths outside DirPath (shouldn't happen, but fsnotify events are best-effort)
+// fall back to DirPath itself.
+func (w *Watcher) affectedSubtree(path string) string {
+	rel, err := filepath.Rel(w.dirPath, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return w.dirPath
+	}
+
+	parts := strings.Split(rel, string(filepath.Separator))
+	return filepath.Join(w.dirPath, parts[0])
+}
+
+// resetDebounceTimerLocked must be called with w.mu held.
+func (w *Watcher) resetDebounceTimerLocked() {
+	if w.debounceTimer != nil {
+		w.debounceTimer.Stop()
+	}
+	w.debounceTimer = time.AfterFunc(watchDebounceInterval, w.triggerScan)
+}
+
+// triggerScan runs a Scan limited to the subtrees that changed since the last one. It's invoked
+// on the debounce timer's own goroutine, never directly from handleEvent.
+func (w *Watcher) triggerScan() {
+	defer util.HandlePanicInModuleThen("scanner/Watcher.triggerScan", func() {})
+
+	w.mu.Lock()
+	affected := make([]string, 0, len(w.pending))
+	for p := range w.pending {
+		affected = append(affected, p)
+	}
+	w.pending = make(map[string]struct{})
+	w.mu.Unlock()
+
+	if len(affected) == 0 {
+		return
+	}
+
+	w.logger.Debug().Strs("paths", affected).Msg("scanner: library change detected, running scoped scan")
+
+	scn := &Scanner{
+		DirPath:              w.dirPath,
+		Username:             w.username,
+		Enhanced:             w.enhanced,
+		AnilistClientWrapper: w.anilistClientWrapper,
+		Logger:               w.logger,
+		WSEventManager:       w.wsEventManager,
+		AffectedPaths:        affected,
+	}
+
+	if _, err := scn.Scan(); err != nil {
+		w.logger.Error().Err(err).Msg("scanner: automatic scan triggered by watcher failed")
+	}
+}
+
+// isWatchLimitError reports whether err is the OS telling us it's out of inotify watch
+// descriptors (Linux's fs.inotify.max_user_watches), as opposed to some other failure.
+func isWatchLimitError(err error) bool {
+	if errors.Is(err, syscall.ENOSPC) {
+		return true
+	}
+	return strings.Contains(err.Error(), "no space left on device") || strings.Contains(err.Error(), "too many open files")
+}
@@ -0,0 +1,141 @@
+// Package scheduler owns a single cron runner shared by the auto-downloader, the library
+// scanner, and any future periodic task - rather than each subsystem rolling its own ticker.
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/robfig/cron/v3"
+	"github.com/rs/zerolog"
+	"sync"
+	"time"
+)
+
+// Task names used to register/look up entries. Subsystems outside this package should refer to
+// their entry by one of these rather than an ad-hoc string, so "run now" and schedule-preview
+// requests can't typo their way into a no-op.
+const (
+	TaskAutoDownloader = "auto-downloader"
+	TaskLibraryScan    = "library-scan"
+)
+
+var ErrTaskNotFound = errors.New("scheduler: task not registered")
+
+// parser matches robfig/cron's classic 5-field expressions (minute hour dom month dow), the
+// format users already know from crontab - no seconds field, no macros like @every.
+var parser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// ValidateCronExpr reports whether expr is a valid 5-field cron expression, without registering
+// anything. Used by settings handlers to reject bad input up front with a clear error.
+func ValidateCronExpr(expr string) error {
+	_, err := parser.Parse(expr)
+	if err != nil {
+		return fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	return nil
+}
+
+type entry struct {
+	id       cron.EntryID
+	schedule cron.Schedule
+	fn       func()
+}
+
+// Scheduler runs cron-scheduled tasks. It's safe for concurrent use.
+type Scheduler struct {
+	cron   *cron.Cron
+	logger *zerolog.Logger
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+// New creates a Scheduler and starts its cron runner. Call Stop when the app shuts down.
+func New(logger *zerolog.Logger) *Scheduler {
+	s := &Scheduler{
+		cron:    cron.New(cron.WithParser(parser)),
+		logger:  logger,
+		entries: make(map[string]*entry),
+	}
+	s.cron.Start()
+	return s
+}
+
+// Stop halts the cron runner, waiting for any in-flight task to finish.
+func (s *Scheduler) Stop() {
+	<-s.cron.Stop().Done()
+}
+
+// Register (re-)registers a task under name with the given cron expression. Calling Register
+// again for the same name replaces the previous entry - this is what lets InitOrRefreshModules
+// tear down and re-register entries from settings on every save without leaking duplicate jobs.
+func (s *Scheduler) Register(name string, cronExpr string, fn func()) error {
+	schedule, err := parser.Parse(cronExpr)
+	if err != nil {
+		return fmt.Errorf("invalid cron expression %q: %w", cronExpr, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.entries[name]; ok {
+		s.cron.Remove(existing.id)
+	}
+
+	id := s.cron.Schedule(schedule, cron.FuncJob(fn))
+	s.entries[name] = &entry{id: id, schedule: schedule, fn: fn}
+
+	s.logger.Debug().Str("task", name).Str("cron", cronExpr).Msg("scheduler: registered task")
+
+	return nil
+}
+
+// Unregister removes name's entry, if any. Registering nothing for a task (e.g. because the
+// settings field driving it was cleared) means it simply never runs again.
+func (s *Scheduler) Unregister(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.entries[name]; ok {
+		s.cron.Remove(existing.id)
+		delete(s.entries, name)
+		s.logger.Debug().Str("task", name).Msg("scheduler: unregistered task")
+	}
+}
+
+// RunNow runs name's task immediately, out-of-band - it does not reset or otherwise affect the
+// task's regular schedule.
+func (s *Scheduler) RunNow(name string) error {
+	s.mu.Lock()
+	e, ok := s.entries[name]
+	s.mu.Unlock()
+
+	if !ok {
+		return ErrTaskNotFound
+	}
+
+	go e.fn()
+
+	return nil
+}
+
+// NextFireTimes returns the next n scheduled run times for name, so the UI can preview a cron
+// expression before saving it.
+func (s *Scheduler) NextFireTimes(name string, n int) ([]time.Time, error) {
+	s.mu.Lock()
+	e, ok := s.entries[name]
+	s.mu.Unlock()
+
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+
+	times := make([]time.Time, 0, n)
+	from := time.Now()
+	for i := 0; i < n; i++ {
+		from = e.schedule.Next(from)
+		times = append(times, from)
+	}
+
+	return times, nil
+}
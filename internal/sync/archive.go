@@ -0,0 +1,220 @@
+package sync
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"github.com/goccy/go-json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// archiveManifestName is the JSON file at the root of an export/import archive listing every
+// snapshot it contains. Everything it references under archiveAssetsPrefix is stored alongside it,
+// keyed by the same relative path SnapshotStore.AssetsDir() resolves snapshots against, so an
+// imported archive keeps working without touching the snapshot rows it restores.
+const (
+	archiveManifestName = "manifest.json"
+	archiveAssetsPrefix = "assets/"
+)
+
+// snapshotArchiveManifest is the root JSON document bundled with every export/import archive.
+type snapshotArchiveManifest struct {
+	AnimeSnapshots []*AnimeSnapshot `json:"animeSnapshots"`
+	MangaSnapshots []*MangaSnapshot `json:"mangaSnapshots"`
+}
+
+// ExportArchive packages every tracked anime & manga snapshot - metadata rows and their
+// banner/cover/episode assets alike - into a single zip archive written to w. This is what lets a
+// user back up their offline library, move it to another device, or seed a fresh install without
+// re-downloading gigabytes of episode thumbnails and cover art.
+func (m *ManagerImpl) ExportArchive(w io.Writer) error {
+	return writeSnapshotArchive(w, newDefaultSnapshotStore(m.localDb, m.localAssetsDir))
+}
+
+// ImportArchive restores every snapshot and asset from an archive written by ExportArchive,
+// upserting snapshot rows and copying assets back into the local assets directory.
+func (m *ManagerImpl) ImportArchive(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("sync: failed to read archive: %w", err)
+	}
+	return readSnapshotArchive(bytes.NewReader(data), int64(len(data)), newDefaultSnapshotStore(m.localDb, m.localAssetsDir))
+}
+
+func writeSnapshotArchive(w io.Writer, store SnapshotStore) error {
+	animeSnapshots, err := store.GetAnimeSnapshots()
+	if err != nil {
+		return fmt.Errorf("sync: failed to read anime snapshots for export: %w", err)
+	}
+	mangaSnapshots, err := store.GetMangaSnapshots()
+	if err != nil {
+		return fmt.Errorf("sync: failed to read manga snapshots for export: %w", err)
+	}
+
+	zw := zip.NewWriter(w)
+
+	manifestData, err := json.Marshal(snapshotArchiveManifest{
+		AnimeSnapshots: animeSnapshots,
+		MangaSnapshots: mangaSnapshots,
+	})
+	if err != nil {
+		_ = zw.Close()
+		return fmt.Errorf("sync: failed to marshal archive manifest: %w", err)
+	}
+
+	mf, err := zw.Create(archiveManifestName)
+	if err != nil {
+		_ = zw.Close()
+		return err
+	}
+	if _, err := mf.Write(manifestData); err != nil {
+		_ = zw.Close()
+		return err
+	}
+
+	assetPaths := make(map[string]struct{})
+	for _, s := range animeSnapshots {
+		addArchiveAssetPath(assetPaths, s.BannerImagePath)
+		addArchiveAssetPath(assetPaths, s.CoverImagePath)
+		for _, p := range s.EpisodeImagePaths {
+			addArchiveAssetPath(assetPaths, p)
+		}
+	}
+	for _, s := range mangaSnapshots {
+		addArchiveAssetPath(assetPaths, s.BannerImagePath)
+		addArchiveAssetPath(assetPaths, s.CoverImagePath)
+	}
+
+	for relPath := range assetPaths {
+		if err := addAssetToArchive(zw, store.AssetsDir(), relPath); err != nil {
+			_ = zw.Close()
+			return fmt.Errorf("sync: failed to archive asset %q: %w", relPath, err)
+		}
+	}
+
+	return zw.Close()
+}
+
+func addArchiveAssetPath(set map[string]struct{}, relPath string) {
+	if relPath == "" {
+		return
+	}
+	set[relPath] = struct{}{}
+}
+
+// addAssetToArchive copies a single asset into the archive. A referenced asset that's already
+// missing from disk (e.g. pruned separately) is skipped rather than failing the whole export.
+func addAssetToArchive(zw *zip.Writer, assetsDir, relPath string) error {
+	src, err := os.Open(filepath.Join(assetsDir, relPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer src.Close()
+
+	dst, err := zw.Create(archiveAssetsPrefix + filepath.ToSlash(relPath))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+func readSnapshotArchive(r io.ReaderAt, size int64, store SnapshotStore) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("sync: failed to open archive: %w", err)
+	}
+
+	var manifest snapshotArchiveManifest
+	manifestFound := false
+
+	for _, f := range zr.File {
+		switch {
+		case f.Name == archiveManifestName:
+			if err := readArchiveJSON(f, &manifest); err != nil {
+				return fmt.Errorf("sync: failed to read archive manifest: %w", err)
+			}
+			manifestFound = true
+		case strings.HasPrefix(f.Name, archiveAssetsPrefix):
+			relPath := filepath.FromSlash(strings.TrimPrefix(f.Name, archiveAssetsPrefix))
+			if err := extractAssetFromArchive(f, store.AssetsDir(), relPath); err != nil {
+				return fmt.Errorf("sync: failed to extract asset %q: %w", relPath, err)
+			}
+		}
+	}
+
+	if !manifestFound {
+		return fmt.Errorf("sync: archive is missing %s", archiveManifestName)
+	}
+
+	for _, snapshot := range manifest.AnimeSnapshots {
+		if err := store.SaveAnimeSnapshot(snapshot); err != nil {
+			return fmt.Errorf("sync: failed to restore anime snapshot for media %d: %w", snapshot.MediaId, err)
+		}
+	}
+	for _, snapshot := range manifest.MangaSnapshots {
+		if err := store.SaveMangaSnapshot(snapshot); err != nil {
+			return fmt.Errorf("sync: failed to restore manga snapshot for media %d: %w", snapshot.MediaId, err)
+		}
+	}
+
+	return nil
+}
+
+func readArchiveJSON(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func extractAssetFromArchive(f *zip.File, assetsDir, relPath string) error {
+	dstPath := filepath.Join(assetsDir, relPath)
+
+	// Guard against zip-slip: a crafted archive entry like "assets/../../../etc/whatever" must
+	// not be allowed to resolve outside assetsDir. This is untrusted input - the whole point of
+	// import is to load an archive that may have come from another device.
+	assetsDirAbs, err := filepath.Abs(assetsDir)
+	if err != nil {
+		return err
+	}
+	dstPathAbs, err := filepath.Abs(dstPath)
+	if err != nil {
+		return err
+	}
+	if dstPathAbs != assetsDirAbs && !strings.HasPrefix(dstPathAbs, assetsDirAbs+string(filepath.Separator)) {
+		return fmt.Errorf("sync: archive entry %q escapes the assets directory", relPath)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0o755); err != nil {
+		return err
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	dst, err := os.Create(dstPath)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	_, err = io.Copy(dst, rc)
+	return err
+}
@@ -0,0 +1,294 @@
+package sync
+
+import (
+	"fmt"
+	"github.com/cespare/xxhash/v2"
+	"github.com/samber/lo"
+	"seanime/internal/api/anilist"
+	"sort"
+	"strings"
+)
+
+// buildAnimeListEntryFromRemote finds mediaId in remoteCollection and, if present, builds the
+// local-collection entry synchronizeCollections would have produced for it from snapshot, along
+// with the status list it belongs in. ok is false if mediaId is no longer in the remote collection,
+// which means it should be removed from the local collection rather than patched.
+func buildAnimeListEntryFromRemote(remoteCollection *anilist.AnimeCollection, mediaId int, snapshot *AnimeSnapshot) (entry *anilist.AnimeListEntry, status anilist.MediaListStatus, ok bool) {
+	for _, remoteList := range remoteCollection.MediaListCollection.GetLists() {
+		if remoteList.GetStatus() == nil {
+			continue
+		}
+		for _, remoteEntry := range remoteList.GetEntries() {
+			if remoteEntry.GetMedia().GetID() != mediaId {
+				continue
+			}
+
+			editedAnime := BaseAnimeDeepCopy(remoteEntry.Media)
+			editedAnime.BannerImage = FormatAssetUrl(snapshot.MediaId, snapshot.BannerImagePath)
+			editedAnime.CoverImage = &anilist.BaseAnime_CoverImage{
+				ExtraLarge: FormatAssetUrl(snapshot.MediaId, snapshot.CoverImagePath),
+				Large:      FormatAssetUrl(snapshot.MediaId, snapshot.CoverImagePath),
+				Medium:     FormatAssetUrl(snapshot.MediaId, snapshot.CoverImagePath),
+				Color:      FormatAssetUrl(snapshot.MediaId, snapshot.CoverImagePath),
+			}
+
+			var startedAt *anilist.AnimeCollection_MediaListCollection_Lists_Entries_StartedAt
+			if remoteEntry.StartedAt != nil {
+				startedAt = &anilist.AnimeCollection_MediaListCollection_Lists_Entries_StartedAt{
+					Year:  ToNewPointer(remoteEntry.StartedAt.GetYear()),
+					Month: ToNewPointer(remoteEntry.StartedAt.GetMonth()),
+					Day:   ToNewPointer(remoteEntry.StartedAt.GetDay()),
+				}
+			}
+
+			var completedAt *anilist.AnimeCollection_MediaListCollection_Lists_Entries_CompletedAt
+			if remoteEntry.CompletedAt != nil {
+				completedAt = &anilist.AnimeCollection_MediaListCollection_Lists_Entries_CompletedAt{
+					Year:  ToNewPointer(remoteEntry.CompletedAt.GetYear()),
+					Month: ToNewPointer(remoteEntry.CompletedAt.GetMonth()),
+					Day:   ToNewPointer(remoteEntry.CompletedAt.GetDay()),
+				}
+			}
+
+			entry = &anilist.AnimeListEntry{
+				ID:          remoteEntry.ID,
+				Score:       ToNewPointer(remoteEntry.Score),
+				Progress:    ToNewPointer(remoteEntry.Progress),
+				Status:      ToNewPointer(remoteEntry.Status),
+				Notes:       ToNewPointer(remoteEntry.Notes),
+				Repeat:      ToNewPointer(remoteEntry.Repeat),
+				Private:     ToNewPointer(remoteEntry.Private),
+				StartedAt:   startedAt,
+				CompletedAt: completedAt,
+				Media:       editedAnime,
+			}
+			return entry, *remoteList.GetStatus(), true
+		}
+	}
+	return nil, "", false
+}
+
+// buildMangaListEntryFromRemote is buildAnimeListEntryFromRemote for manga.
+func buildMangaListEntryFromRemote(remoteCollection *anilist.MangaCollection, mediaId int, snapshot *MangaSnapshot) (entry *anilist.MangaListEntry, status anilist.MediaListStatus, ok bool) {
+	for _, remoteList := range remoteCollection.MediaListCollection.GetLists() {
+		if remoteList.GetStatus() == nil {
+			continue
+		}
+		for _, remoteEntry := range remoteList.GetEntries() {
+			if remoteEntry.GetMedia().GetID() != mediaId {
+				continue
+			}
+
+			editedManga := BaseMangaDeepCopy(remoteEntry.Media)
+			editedManga.BannerImage = FormatAssetUrl(snapshot.MediaId, snapshot.BannerImagePath)
+			editedManga.CoverImage = &anilist.BaseManga_CoverImage{
+				ExtraLarge: FormatAssetUrl(snapshot.MediaId, snapshot.CoverImagePath),
+				Large:      FormatAssetUrl(snapshot.MediaId, snapshot.CoverImagePath),
+				Medium:     FormatAssetUrl(snapshot.MediaId, snapshot.CoverImagePath),
+				Color:      FormatAssetUrl(snapshot.MediaId, snapshot.CoverImagePath),
+			}
+
+			var startedAt *anilist.MangaCollection_MediaListCollection_Lists_Entries_StartedAt
+			if remoteEntry.StartedAt != nil {
+				startedAt = &anilist.MangaCollection_MediaListCollection_Lists_Entries_StartedAt{
+					Year:  ToNewPointer(remoteEntry.StartedAt.GetYear()),
+					Month: ToNewPointer(remoteEntry.StartedAt.GetMonth()),
+					Day:   ToNewPointer(remoteEntry.StartedAt.GetDay()),
+				}
+			}
+
+			var completedAt *anilist.MangaCollection_MediaListCollection_Lists_Entries_CompletedAt
+			if remoteEntry.CompletedAt != nil {
+				completedAt = &anilist.MangaCollection_MediaListCollection_Lists_Entries_CompletedAt{
+					Year:  ToNewPointer(remoteEntry.CompletedAt.GetYear()),
+					Month: ToNewPointer(remoteEntry.CompletedAt.GetMonth()),
+					Day:   ToNewPointer(remoteEntry.CompletedAt.GetDay()),
+				}
+			}
+
+			entry = &anilist.MangaListEntry{
+				ID:          remoteEntry.ID,
+				Score:       ToNewPointer(remoteEntry.Score),
+				Progress:    ToNewPointer(remoteEntry.Progress),
+				Status:      ToNewPointer(remoteEntry.Status),
+				Notes:       ToNewPointer(remoteEntry.Notes),
+				Repeat:      ToNewPointer(remoteEntry.Repeat),
+				Private:     ToNewPointer(remoteEntry.Private),
+				StartedAt:   startedAt,
+				CompletedAt: completedAt,
+				Media:       editedManga,
+			}
+			return entry, *remoteList.GetStatus(), true
+		}
+	}
+	return nil, "", false
+}
+
+// applyAnimeCollectionPatch incrementally updates the already-loaded local anime collection for a
+// single tracked anime instead of waiting for the next full synchronizeCollections rebuild to pick
+// it up - moving it between status lists if the remote status changed, or dropping it if it's no
+// longer in the remote collection. It hands only this one entry to the SnapshotStore's
+// Upsert/Remove methods, and skips the walk and deep copy of every tracked entry that a full
+// rebuild does; the default store still re-saves the whole collection to persist the change.
+//
+// It returns false if it couldn't apply the patch cleanly (local collection not loaded yet, or the
+// entry's remote status doesn't match any known local list), in which case the caller should mark
+// the local collections dirty so the next checkAndUpdateLocalCollections falls back to a full rebuild.
+func (q *Syncer) applyAnimeCollectionPatch(diff *AnimeDiffResult, snapshot *AnimeSnapshot) bool {
+	// Guards against synchronizeCollections concurrently rebuilding and swapping
+	// manager.localAnimeCollection out from under this read-modify-write - see collectionsMu.
+	q.collectionsMu.Lock()
+	defer q.collectionsMu.Unlock()
+
+	if q.manager.localAnimeCollection.IsAbsent() || q.manager.animeCollection.IsAbsent() {
+		return false
+	}
+
+	mediaId := diff.AnimeEntry.Media.ID
+	localCollection := q.manager.localAnimeCollection.MustGet()
+
+	for _, list := range localCollection.MediaListCollection.GetLists() {
+		list.Entries = lo.Filter(list.Entries, func(e *anilist.AnimeListEntry, _ int) bool {
+			return e.GetMedia().GetID() != mediaId
+		})
+	}
+
+	entry, status, found := buildAnimeListEntryFromRemote(q.manager.animeCollection.MustGet(), mediaId, snapshot)
+	if !found {
+		if err := q.store.RemoveAnimeListEntry(mediaId, localCollection); err != nil {
+			q.manager.logger.Error().Err(err).Msgf("sync: Failed to remove anime %d from the local collection", mediaId)
+			return false
+		}
+		return true
+	}
+
+	for _, list := range localCollection.MediaListCollection.GetLists() {
+		if list.GetStatus() == nil || *list.GetStatus() != status {
+			continue
+		}
+		list.Entries = append(list.Entries, entry)
+
+		if err := q.store.UpsertAnimeListEntry(mediaId, entry, status, localCollection); err != nil {
+			q.manager.logger.Error().Err(err).Msgf("sync: Failed to persist anime %d to the local collection", mediaId)
+			return false
+		}
+		return true
+	}
+
+	// The remote status doesn't match any list we know about locally (e.g. a brand new custom
+	// list) - fall back to a full rebuild rather than silently dropping the entry.
+	return false
+}
+
+// applyMangaCollectionPatch is applyAnimeCollectionPatch for manga.
+func (q *Syncer) applyMangaCollectionPatch(diff *MangaDiffResult, snapshot *MangaSnapshot) bool {
+	// Guards against synchronizeCollections concurrently rebuilding and swapping
+	// manager.localMangaCollection out from under this read-modify-write - see collectionsMu.
+	q.collectionsMu.Lock()
+	defer q.collectionsMu.Unlock()
+
+	if q.manager.localMangaCollection.IsAbsent() || q.manager.mangaCollection.IsAbsent() {
+		return false
+	}
+
+	mediaId := diff.MangaEntry.Media.ID
+	localCollection := q.manager.localMangaCollection.MustGet()
+
+	for _, list := range localCollection.MediaListCollection.GetLists() {
+		list.Entries = lo.Filter(list.Entries, func(e *anilist.MangaListEntry, _ int) bool {
+			return e.GetMedia().GetID() != mediaId
+		})
+	}
+
+	entry, status, found := buildMangaListEntryFromRemote(q.manager.mangaCollection.MustGet(), mediaId, snapshot)
+	if !found {
+		if err := q.store.RemoveMangaListEntry(mediaId, localCollection); err != nil {
+			q.manager.logger.Error().Err(err).Msgf("sync: Failed to remove manga %d from the local collection", mediaId)
+			return false
+		}
+		return true
+	}
+
+	for _, list := range localCollection.MediaListCollection.GetLists() {
+		if list.GetStatus() == nil || *list.GetStatus() != status {
+			continue
+		}
+		list.Entries = append(list.Entries, entry)
+
+		if err := q.store.UpsertMangaListEntry(mediaId, entry, status, localCollection); err != nil {
+			q.manager.logger.Error().Err(err).Msgf("sync: Failed to persist manga %d to the local collection", mediaId)
+			return false
+		}
+		return true
+	}
+
+	return false
+}
+
+// markCollectionsDirty forces the next checkAndUpdateLocalCollections pass to run a full
+// synchronizeCollections rebuild instead of trusting the incremental patches - used whenever a
+// patch couldn't be applied cleanly.
+func (q *Syncer) markCollectionsDirty() {
+	q.mu.Lock()
+	q.collectionsReady = false
+	q.mu.Unlock()
+}
+
+// collectionsDrifted reports whether the remote anime/manga collections have changed in a way the
+// incremental patches wouldn't have caught (e.g. list memberships edited directly on AniList by
+// another client mid-batch). It's deliberately cheap - no deep copies, no DB writes - so it's safe
+// to call on every checkAndUpdateLocalCollections pass instead of only at startup.
+func (q *Syncer) collectionsDrifted() bool {
+	if q.manager.animeCollection.IsAbsent() || q.manager.mangaCollection.IsAbsent() {
+		return false
+	}
+	return computeAnimeChecksum(q.manager.animeCollection.MustGet(), q.trackedAnimeMap) != q.animeChecksum ||
+		computeMangaChecksum(q.manager.mangaCollection.MustGet(), q.trackedMangaMap) != q.mangaChecksum
+}
+
+// computeAnimeChecksum fingerprints the tracked subset of a remote anime collection (media ID,
+// status, progress) so collectionsDrifted can detect changes the incremental patches didn't apply.
+func computeAnimeChecksum(collection *anilist.AnimeCollection, tracked map[int]*TrackedMedia) uint64 {
+	fingerprints := make([]string, 0, len(tracked))
+	for _, list := range collection.MediaListCollection.GetLists() {
+		if list.GetStatus() == nil {
+			continue
+		}
+		for _, entry := range list.GetEntries() {
+			mediaId := entry.GetMedia().GetID()
+			if _, ok := tracked[mediaId]; !ok {
+				continue
+			}
+			fingerprints = append(fingerprints, entryFingerprint(mediaId, string(*list.GetStatus()), entry.Progress))
+		}
+	}
+	sort.Strings(fingerprints)
+	return xxhash.Sum64String(strings.Join(fingerprints, "|"))
+}
+
+// computeMangaChecksum is computeAnimeChecksum for manga.
+func computeMangaChecksum(collection *anilist.MangaCollection, tracked map[int]*TrackedMedia) uint64 {
+	fingerprints := make([]string, 0, len(tracked))
+	for _, list := range collection.MediaListCollection.GetLists() {
+		if list.GetStatus() == nil {
+			continue
+		}
+		for _, entry := range list.GetEntries() {
+			mediaId := entry.GetMedia().GetID()
+			if _, ok := tracked[mediaId]; !ok {
+				continue
+			}
+			fingerprints = append(fingerprints, entryFingerprint(mediaId, string(*list.GetStatus()), entry.Progress))
+		}
+	}
+	sort.Strings(fingerprints)
+	return xxhash.Sum64String(strings.Join(fingerprints, "|"))
+}
+
+func entryFingerprint(mediaId int, status string, progress *int) string {
+	p := 0
+	if progress != nil {
+		p = *progress
+	}
+	return fmt.Sprintf("%d:%s:%d", mediaId, status, p)
+}
@@ -0,0 +1,147 @@
+package sync
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+)
+
+// jobPriority ranks a queued AnimeJob/MangaJob by urgency. A title with no offline snapshot yet
+// (DiffTypeMissing) is what a user is actively waiting on, so it jumps ahead of metadata refreshes
+// and background housekeeping instead of sitting behind whatever was queued first.
+type jobPriority int
+
+const (
+	jobPriorityMissing    jobPriority = iota // DiffTypeMissing - nothing offline yet
+	jobPriorityMetadata                      // DiffTypeMetadata - refreshing an existing snapshot
+	jobPriorityBackground                    // anything else
+)
+
+func animeJobPriority(diff *AnimeDiffResult) jobPriority {
+	switch diff.DiffType {
+	case DiffTypeMissing:
+		return jobPriorityMissing
+	case DiffTypeMetadata:
+		return jobPriorityMetadata
+	default:
+		return jobPriorityBackground
+	}
+}
+
+func mangaJobPriority(diff *MangaDiffResult) jobPriority {
+	switch diff.DiffType {
+	case DiffTypeMissing:
+		return jobPriorityMissing
+	case DiffTypeMetadata:
+		return jobPriorityMetadata
+	default:
+		return jobPriorityBackground
+	}
+}
+
+// queuedJob wraps a job with its priority and insertion order, so the heap can break priority
+// ties in FIFO order.
+type queuedJob[T any] struct {
+	job      T
+	priority jobPriority
+	seq      int64
+}
+
+// jobHeap implements container/heap.Interface, ordering by priority then seq.
+type jobHeap[T any] []*queuedJob[T]
+
+func (h jobHeap[T]) Len() int { return len(h) }
+func (h jobHeap[T]) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority < h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+func (h jobHeap[T]) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *jobHeap[T]) Push(x any)   { *h = append(*h, x.(*queuedJob[T])) }
+func (h *jobHeap[T]) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
+// jobQueue is an unbounded, priority-ordered replacement for the fixed-cap buffered channels
+// Syncer used to queue jobs in - a user tracking more than the old cap of 100 titles at once no
+// longer blocks runDiffs, and DiffTypeMissing jobs are always popped before background refreshes.
+type jobQueue[T any] struct {
+	mu       sync.Mutex
+	heap     jobHeap[T]
+	seq      int64
+	notEmpty chan struct{}
+	closed   bool
+}
+
+func newJobQueue[T any]() *jobQueue[T] {
+	return &jobQueue[T]{notEmpty: make(chan struct{}, 1)}
+}
+
+// Push adds a job at the given priority.
+func (q *jobQueue[T]) Push(job T, priority jobPriority) {
+	q.mu.Lock()
+	q.seq++
+	heap.Push(&q.heap, &queuedJob[T]{job: job, priority: priority, seq: q.seq})
+	q.mu.Unlock()
+
+	select {
+	case q.notEmpty <- struct{}{}:
+	default:
+	}
+}
+
+// Pop blocks until a job is available, ctx is cancelled, or the queue is closed - in which case ok
+// is false.
+func (q *jobQueue[T]) Pop(ctx context.Context) (job T, ok bool) {
+	for {
+		q.mu.Lock()
+		if len(q.heap) > 0 {
+			item := heap.Pop(&q.heap).(*queuedJob[T])
+			q.mu.Unlock()
+			return item.job, true
+		}
+		closed := q.closed
+		q.mu.Unlock()
+
+		if closed {
+			// Pass the wake-up on, so any other blocked Pop also sees the close.
+			select {
+			case q.notEmpty <- struct{}{}:
+			default:
+			}
+			return job, false
+		}
+
+		select {
+		case <-q.notEmpty:
+		case <-ctx.Done():
+			return job, false
+		}
+	}
+}
+
+// Len returns the number of jobs currently waiting.
+func (q *jobQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.heap)
+}
+
+// Close makes every blocked Pop return with ok=false, and every later Pop too once the jobs still
+// queued have been handed out.
+func (q *jobQueue[T]) Close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+
+	select {
+	case q.notEmpty <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,96 @@
+package sync
+
+import (
+	"context"
+	"time"
+)
+
+// defaultMetadataRequestsPerSecond and defaultMaxConcurrentDownloads bound how hard a bulk sync
+// hammers AniList and image CDNs. SetRateLimit lets these be tuned at runtime (e.g. from settings)
+// without changing NewQueue's signature.
+const (
+	defaultMetadataRequestsPerSecond = 2
+	defaultMaxConcurrentDownloads    = 3
+)
+
+// tokenBucket is a minimal token-bucket rate limiter: it starts full, refills one token every
+// 1/ratePerSecond, and Wait blocks once it's empty.
+type tokenBucket struct {
+	tokens chan struct{}
+	stop   chan struct{}
+}
+
+func newTokenBucket(ratePerSecond int) *tokenBucket {
+	if ratePerSecond <= 0 {
+		ratePerSecond = 1
+	}
+
+	tb := &tokenBucket{
+		tokens: make(chan struct{}, ratePerSecond),
+		stop:   make(chan struct{}),
+	}
+	for i := 0; i < ratePerSecond; i++ {
+		tb.tokens <- struct{}{}
+	}
+
+	go tb.refill(time.Second / time.Duration(ratePerSecond))
+
+	return tb
+}
+
+func (tb *tokenBucket) refill(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			select {
+			case tb.tokens <- struct{}{}:
+			default:
+			}
+		case <-tb.stop:
+			return
+		}
+	}
+}
+
+// Stop ends tb's refill goroutine. Call it once tb is no longer in use (e.g. when SetRateLimit
+// swaps it out for a new one) - refill otherwise runs forever since nothing else ever signals it.
+func (tb *tokenBucket) Stop() {
+	close(tb.stop)
+}
+
+// Wait blocks until a token is available or ctx is cancelled.
+func (tb *tokenBucket) Wait(ctx context.Context) error {
+	select {
+	case <-tb.tokens:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// semaphore bounds how many downloads can run at once.
+type semaphore chan struct{}
+
+func newSemaphore(n int) semaphore {
+	if n <= 0 {
+		n = 1
+	}
+	return make(semaphore, n)
+}
+
+// Acquire blocks until a slot is free or ctx is cancelled.
+func (s semaphore) Acquire(ctx context.Context) error {
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s semaphore) Release() {
+	<-s
+}
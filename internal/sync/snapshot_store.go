@@ -0,0 +1,95 @@
+package sync
+
+import (
+	"seanime/internal/api/anilist"
+	"seanime/internal/database"
+)
+
+// SnapshotStore is where synchronizeAnime, synchronizeManga and synchronizeCollections persist
+// everything sync produces - snapshot rows, the local collections, and the banner/cover/episode
+// assets those snapshots point at. defaultSnapshotStore backs it with the existing SQLite database
+// and local assets directory; ExportArchive/ImportArchive on ManagerImpl read and write the same
+// data as a single portable zip archive instead, so a user's offline library can move between
+// devices without re-downloading it.
+type SnapshotStore interface {
+	GetAnimeSnapshots() ([]*AnimeSnapshot, error)
+	SaveAnimeSnapshot(snapshot *AnimeSnapshot) error
+
+	GetMangaSnapshots() ([]*MangaSnapshot, error)
+	SaveMangaSnapshot(snapshot *MangaSnapshot) error
+
+	SaveAnimeCollection(collection *anilist.AnimeCollection) error
+	SaveMangaCollection(collection *anilist.MangaCollection) error
+
+	// UpsertAnimeListEntry and RemoveAnimeListEntry are called by the incremental collection
+	// patcher after it has already patched collection in place - mediaId/entry/status describe
+	// the change for logging/future per-row backing, but the database doesn't yet have a way to
+	// persist a single local-collection row, so these just re-save collection wholesale via
+	// SaveAnimeCollection. That still saves the re-matching/re-fetching work the patcher exists
+	// to avoid, just not the serialization cost.
+	UpsertAnimeListEntry(mediaId int, entry *anilist.AnimeListEntry, status anilist.MediaListStatus, collection *anilist.AnimeCollection) error
+	RemoveAnimeListEntry(mediaId int, collection *anilist.AnimeCollection) error
+
+	UpsertMangaListEntry(mediaId int, entry *anilist.MangaListEntry, status anilist.MediaListStatus, collection *anilist.MangaCollection) error
+	RemoveMangaListEntry(mediaId int, collection *anilist.MangaCollection) error
+
+	// AssetsDir is where Download*Images should write banner/cover/episode images - snapshots
+	// record paths relative to it.
+	AssetsDir() string
+}
+
+// defaultSnapshotStore is the SnapshotStore backed by the local database and the on-disk assets
+// directory - every Syncer uses it, and ExportArchive/ImportArchive read from and write to it.
+type defaultSnapshotStore struct {
+	db        *database.Database
+	assetsDir string
+}
+
+// newDefaultSnapshotStore builds the SnapshotStore a Syncer uses by default.
+func newDefaultSnapshotStore(db *database.Database, assetsDir string) *defaultSnapshotStore {
+	return &defaultSnapshotStore{db: db, assetsDir: assetsDir}
+}
+
+func (s *defaultSnapshotStore) GetAnimeSnapshots() ([]*AnimeSnapshot, error) {
+	return s.db.GetAnimeSnapshots()
+}
+
+func (s *defaultSnapshotStore) SaveAnimeSnapshot(snapshot *AnimeSnapshot) error {
+	return s.db.SaveAnimeSnapshot(snapshot)
+}
+
+func (s *defaultSnapshotStore) GetMangaSnapshots() ([]*MangaSnapshot, error) {
+	return s.db.GetMangaSnapshots()
+}
+
+func (s *defaultSnapshotStore) SaveMangaSnapshot(snapshot *MangaSnapshot) error {
+	return s.db.SaveMangaSnapshot(snapshot)
+}
+
+func (s *defaultSnapshotStore) SaveAnimeCollection(collection *anilist.AnimeCollection) error {
+	return s.db.SaveAnimeCollection(collection)
+}
+
+func (s *defaultSnapshotStore) SaveMangaCollection(collection *anilist.MangaCollection) error {
+	return s.db.SaveMangaCollection(collection)
+}
+
+func (s *defaultSnapshotStore) UpsertAnimeListEntry(_ int, _ *anilist.AnimeListEntry, _ anilist.MediaListStatus, collection *anilist.AnimeCollection) error {
+	return s.db.SaveAnimeCollection(collection)
+}
+
+func (s *defaultSnapshotStore) RemoveAnimeListEntry(_ int, collection *anilist.AnimeCollection) error {
+	return s.db.SaveAnimeCollection(collection)
+}
+
+func (s *defaultSnapshotStore) UpsertMangaListEntry(_ int, _ *anilist.MangaListEntry, _ anilist.MediaListStatus, collection *anilist.MangaCollection) error {
+	return s.db.SaveMangaCollection(collection)
+}
+
+func (s *defaultSnapshotStore) RemoveMangaListEntry(_ int, collection *anilist.MangaCollection) error {
+	return s.db.SaveMangaCollection(collection)
+}
+
+func (s *defaultSnapshotStore) AssetsDir() string {
+	return s.assetsDir
+}
@@ -1,21 +1,31 @@
 package sync
 
 import (
+	"context"
+	"fmt"
 	"github.com/samber/lo"
 	"github.com/samber/mo"
 	"seanime/internal/api/anilist"
 	"seanime/internal/api/metadata"
+	"seanime/internal/events"
 	"seanime/internal/library/anime"
 	"seanime/internal/manga"
 	"seanime/internal/util"
 	"seanime/internal/util/result"
 	"sync"
+	"time"
 )
 
 // DEVNOTE: The synchronization process is split into 3 parts:
 // 1. ManagerImpl.synchronize removes outdated tracked anime & manga, runs Syncer.runDiffs and adds changed tracked anime & manga to the queue.
 // 2. The Syncer processes the queue, calling Syncer.synchronizeAnime and Syncer.synchronizeManga for each job.
 // 3. Syncer.synchronizeCollections creates a local collection that mirrors the remote collection, containing only the tracked anime & manga. Only called when the queue is emptied.
+//
+// SynchronizeNow is a fourth, on-demand path: it diffs and snapshots a single tracked anime or
+// manga immediately instead of waiting for the next runDiffs/processXJobs cycle, which is what a
+// user who just tracked a title is actually waiting on. DownloadAnimeImages, DownloadMangaImages
+// and DownloadAnimeEpisodeImages take the caller's context.Context as their first argument so a
+// cancelled SynchronizeNow actually aborts an in-flight image download instead of finishing it.
 
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
@@ -26,26 +36,116 @@ type (
 	//
 	// Synchronization can fail due to network issues. When it does, the anime or manga will be added to the failed queue.
 	Syncer struct {
-		animeJobQueue chan AnimeJob
-		mangaJobQueue chan MangaJob
+		animeJobQueue *jobQueue[AnimeJob]
+		mangaJobQueue *jobQueue[MangaJob]
 
 		changedAnimeQueue *result.Cache[int, *AnimeDiffResult]
 		changedMangaQueue *result.Cache[int, *MangaDiffResult]
 
-		failedAnimeQueue *result.Cache[int, *anilist.AnimeListEntry]
-		failedMangaQueue *result.Cache[int, *anilist.MangaListEntry]
+		failedAnimeQueue map[int]*failedQueueItem[*AnimeDiffResult]
+		failedMangaQueue map[int]*failedQueueItem[*MangaDiffResult]
+		failedMu         sync.Mutex
 
 		trackedAnimeMap map[int]*TrackedMedia
 		trackedMangaMap map[int]*TrackedMedia
 
 		manager *ManagerImpl
+		store   SnapshotStore
 		mu      sync.Mutex
 
+		// collectionsMu guards every read and write of manager.localAnimeCollection /
+		// manager.localMangaCollection - both applyAnimeCollectionPatch/applyMangaCollectionPatch
+		// (called from processAnimeJobs/processMangaJobs) and synchronizeCollections's final
+		// swap touch these fields, and the two can run concurrently since synchronizeCollections
+		// runs outside mu (see checkAndUpdateLocalCollections).
+		collectionsMu sync.Mutex
+
 		shouldUpdateLocalCollections bool
 		doneUpdatingLocalCollections chan struct{}
+
+		// collectionsRebuildTimer debounces the local-collection rebuild triggered by SynchronizeNow,
+		// the same way Watcher debounces scan triggers - a burst of on-demand syncs should still only
+		// rebuild the local collections once.
+		collectionsRebuildTimer *time.Timer
+
+		// pauseMu/resumeCh implement Pause/Resume: resumeCh is nil while running, and set to an
+		// open channel while paused - processAnimeJobs/processMangaJobs block on it between jobs,
+		// so sync can yield while the user is actively streaming.
+		pauseMu  sync.Mutex
+		resumeCh chan struct{}
+
+		// metadataLimiter and downloadSemaphore throttle outgoing AniList metadata requests and
+		// concurrent image downloads respectively, so a bulk sync doesn't hammer either. rateMu
+		// guards swapping them out via SetRateLimit.
+		rateMu            sync.Mutex
+		metadataLimiter   *tokenBucket
+		downloadSemaphore semaphore
+
+		// etaMu guards the rolling average job durations publishProgress uses to estimate ETA.
+		etaMu            sync.Mutex
+		avgAnimeDuration time.Duration
+		avgMangaDuration time.Duration
+
+		// collectionsReady, animeChecksum and mangaChecksum back the incremental collection patch
+		// path: collectionsReady is false until the first full synchronizeCollections rebuild has
+		// run, and animeChecksum/mangaChecksum capture the tracked-entry fingerprint as of that
+		// rebuild so collectionsDrifted can tell whether the incremental patches since then still
+		// agree with the remote collections. All three are guarded by mu, same as
+		// shouldUpdateLocalCollections.
+		collectionsReady bool
+		animeChecksum    uint64
+		mangaChecksum    uint64
+
+		progress    QueueProgress
+		progressMu  sync.Mutex
+		subscribers map[chan QueueProgress]struct{}
 	}
 
+	// MediaKind tells SynchronizeNow whether a media ID refers to a tracked anime or manga.
+	MediaKind string
+
+	// SyncPhase identifies which part of the synchronization pipeline a QueueProgress/
+	// SyncProgressEvent was emitted from.
+	SyncPhase string
+
+	// QueueProgress is the Syncer's current aggregate state, returned by GetProgress and embedded
+	// in every SyncProgressEvent so a client that missed earlier events can still render an
+	// accurate progress bar from whichever event it sees next.
 	QueueProgress struct {
+		Phase           SyncPhase `json:"phase"`
+		TotalAnime      int       `json:"totalAnime"`
+		TotalManga      int       `json:"totalManga"`
+		DoneAnime       int       `json:"doneAnime"`
+		DoneManga       int       `json:"doneManga"`
+		CurrentItem     string    `json:"currentItem"`
+		FailedCount     int       `json:"failedCount"`
+		QueueDepthAnime int       `json:"queueDepthAnime"`
+		QueueDepthManga int       `json:"queueDepthManga"`
+		// ETASeconds is a rough estimate based on the rolling average duration of recently
+		// completed jobs - it's 0 until at least one anime or manga job has finished.
+		ETASeconds int64 `json:"etaSeconds"`
+	}
+
+	// SyncProgressEvent is published over the websocket (events.EventSyncProgress), with its
+	// Progress also sent on every Subscribe channel, for diff start/finish, per-item
+	// queued/started/completed/failed, and collection-rebuild finished. No event reports image
+	// download bytes: nothing publishes SyncPhaseImageDownloadBytes.
+	SyncProgressEvent struct {
+		Phase     SyncPhase     `json:"phase"`
+		MediaId   int           `json:"mediaId,omitempty"`
+		MediaType string        `json:"mediaType,omitempty"` // "anime" or "manga"
+		Bytes     int64         `json:"bytes,omitempty"`
+		Error     string        `json:"error,omitempty"`
+		Progress  QueueProgress `json:"progress"`
+	}
+
+	// failedQueueItem keeps a failed diff around with enough bookkeeping for the retry worker to
+	// apply exponential backoff and give up after maxSyncRetries attempts.
+	failedQueueItem[T any] struct {
+		diff      T
+		attempts  int
+		nextRetry time.Time
+		exhausted bool
 	}
 
 	AnimeJob struct {
@@ -56,58 +156,308 @@ type (
 	}
 )
 
+const (
+	SyncPhaseDiffStart     SyncPhase = "diff-start"
+	SyncPhaseDiffEnd       SyncPhase = "diff-end"
+	SyncPhaseItemQueued    SyncPhase = "item-queued"
+	SyncPhaseItemStarted   SyncPhase = "item-started"
+	SyncPhaseItemCompleted SyncPhase = "item-completed"
+	SyncPhaseItemFailed    SyncPhase = "item-failed"
+	// SyncPhaseImageDownloadBytes is reserved for byte-level image download progress. DownloadAnimeImages,
+	// DownloadMangaImages and DownloadAnimeEpisodeImages don't currently accept a progress callback, so
+	// nothing publishes this phase yet - it's defined now so callers can switch on it once they do.
+	SyncPhaseImageDownloadBytes SyncPhase = "image-download-bytes"
+	SyncPhaseCollectionsRebuilt SyncPhase = "collections-rebuilt"
+)
+
+// maxSyncRetries is how many failed attempts, the first run included, an anime or manga may have
+// before the retry worker parks it, so it is retried automatically at most maxSyncRetries-1 times -
+// RetryFailed can still be used to force one more attempt.
+const maxSyncRetries = 5
+
+// retryCheckInterval is how often the retry worker wakes up to check for failed items whose
+// backoff has elapsed.
+const retryCheckInterval = 1 * time.Minute
+
+const (
+	MediaKindAnime MediaKind = "anime"
+	MediaKindManga MediaKind = "manga"
+)
+
+// collectionsRebuildDebounce is how long scheduleCollectionsRebuild waits for more SynchronizeNow
+// calls before actually rebuilding the local collections.
+const collectionsRebuildDebounce = 500 * time.Millisecond
+
 func NewQueue(manager *ManagerImpl) *Syncer {
 	ret := &Syncer{
-		animeJobQueue:                make(chan AnimeJob, 100),
-		mangaJobQueue:                make(chan MangaJob, 100),
+		animeJobQueue:                newJobQueue[AnimeJob](),
+		mangaJobQueue:                newJobQueue[MangaJob](),
 		changedAnimeQueue:            result.NewCache[int, *AnimeDiffResult](),
 		changedMangaQueue:            result.NewCache[int, *MangaDiffResult](),
-		failedAnimeQueue:             result.NewCache[int, *anilist.AnimeListEntry](),
-		failedMangaQueue:             result.NewCache[int, *anilist.MangaListEntry](),
+		failedAnimeQueue:             make(map[int]*failedQueueItem[*AnimeDiffResult]),
+		failedMangaQueue:             make(map[int]*failedQueueItem[*MangaDiffResult]),
 		shouldUpdateLocalCollections: false,
 		doneUpdatingLocalCollections: make(chan struct{}, 1),
+		subscribers:                  make(map[chan QueueProgress]struct{}),
 		manager:                      manager,
+		store:                        newDefaultSnapshotStore(manager.localDb, manager.localAssetsDir),
+		metadataLimiter:              newTokenBucket(defaultMetadataRequestsPerSecond),
+		downloadSemaphore:            newSemaphore(defaultMaxConcurrentDownloads),
 	}
 
 	go ret.processAnimeJobs()
 	go ret.processMangaJobs()
+	go ret.retryFailedLoop()
 
 	return ret
 }
 
+// SetRateLimit reconfigures how many metadata requests per second and concurrent downloads the
+// Syncer allows itself - e.g. when a user turns bulk-sync throttling up or down from settings.
+func (q *Syncer) SetRateLimit(metadataRequestsPerSecond, maxConcurrentDownloads int) {
+	q.rateMu.Lock()
+	defer q.rateMu.Unlock()
+	old := q.metadataLimiter
+	q.metadataLimiter = newTokenBucket(metadataRequestsPerSecond)
+	q.downloadSemaphore = newSemaphore(maxConcurrentDownloads)
+	if old != nil {
+		old.Stop()
+	}
+}
+
+// rateLimiters returns the current metadata limiter and download semaphore - called on every
+// metadata fetch and image download since SetRateLimit can swap them out mid-sync.
+func (q *Syncer) rateLimiters() (*tokenBucket, semaphore) {
+	q.rateMu.Lock()
+	defer q.rateMu.Unlock()
+	return q.metadataLimiter, q.downloadSemaphore
+}
+
+// Pause stops the anime/manga workers from picking up new jobs once their current job finishes -
+// e.g. while the user is actively streaming and shouldn't compete with sync for bandwidth. Diffing
+// and queueing still happen normally; only processing is held back.
+func (q *Syncer) Pause() {
+	q.pauseMu.Lock()
+	defer q.pauseMu.Unlock()
+	if q.resumeCh == nil {
+		q.resumeCh = make(chan struct{})
+	}
+}
+
+// Resume lets the anime/manga workers continue processing jobs after Pause.
+func (q *Syncer) Resume() {
+	q.pauseMu.Lock()
+	defer q.pauseMu.Unlock()
+	if q.resumeCh != nil {
+		close(q.resumeCh)
+		q.resumeCh = nil
+	}
+}
+
+// waitIfPaused blocks while the Syncer is paused.
+func (q *Syncer) waitIfPaused() {
+	for {
+		q.pauseMu.Lock()
+		ch := q.resumeCh
+		q.pauseMu.Unlock()
+		if ch == nil {
+			return
+		}
+		<-ch
+	}
+}
+
+// GetProgress returns the Syncer's current aggregate progress.
+func (q *Syncer) GetProgress() QueueProgress {
+	q.progressMu.Lock()
+	defer q.progressMu.Unlock()
+	return q.progress
+}
+
+// Subscribe returns a channel that receives every QueueProgress update, and an unsubscribe
+// function that must be called once the caller is done listening. The channel is buffered and
+// updates are sent non-blocking, so a slow subscriber drops updates rather than stalling sync.
+func (q *Syncer) Subscribe() (<-chan QueueProgress, func()) {
+	ch := make(chan QueueProgress, 8)
+
+	q.progressMu.Lock()
+	q.subscribers[ch] = struct{}{}
+	q.progressMu.Unlock()
+
+	unsubscribe := func() {
+		q.progressMu.Lock()
+		if _, ok := q.subscribers[ch]; ok {
+			delete(q.subscribers, ch)
+			close(ch)
+		}
+		q.progressMu.Unlock()
+	}
+
+	return ch, unsubscribe
+}
+
+// publishProgress updates the aggregate QueueProgress for phase, then broadcasts it to every
+// Subscribe-r and over the websocket as a SyncProgressEvent.
+func (q *Syncer) publishProgress(phase SyncPhase, mediaId int, mediaType string, bytes int64, errMsg string) {
+	q.progressMu.Lock()
+	q.progress.Phase = phase
+	switch phase {
+	case SyncPhaseItemQueued:
+		if mediaType == "anime" {
+			q.progress.TotalAnime++
+		} else {
+			q.progress.TotalManga++
+		}
+	case SyncPhaseItemStarted:
+		q.progress.CurrentItem = fmt.Sprintf("%s:%d", mediaType, mediaId)
+	case SyncPhaseItemCompleted:
+		if mediaType == "anime" {
+			q.progress.DoneAnime++
+		} else {
+			q.progress.DoneManga++
+		}
+		q.progress.CurrentItem = ""
+	case SyncPhaseItemFailed:
+		q.progress.FailedCount++
+		q.progress.CurrentItem = ""
+	}
+
+	q.progress.QueueDepthAnime = q.animeJobQueue.Len()
+	q.progress.QueueDepthManga = q.mangaJobQueue.Len()
+	q.progress.ETASeconds = int64(q.estimateRemaining().Seconds())
+
+	snapshot := q.progress
+	for ch := range q.subscribers {
+		select {
+		case ch <- snapshot:
+		default:
+		}
+	}
+	q.progressMu.Unlock()
+
+	q.manager.wsEventManager.SendEvent(events.EventSyncProgress, SyncProgressEvent{
+		Phase:     phase,
+		MediaId:   mediaId,
+		MediaType: mediaType,
+		Bytes:     bytes,
+		Error:     errMsg,
+		Progress:  snapshot,
+	})
+}
+
 func (q *Syncer) processAnimeJobs() {
-	for job := range q.animeJobQueue {
+	for {
+		q.waitIfPaused()
+
+		job, ok := q.animeJobQueue.Pop(context.Background())
+		if !ok {
+			return
+		}
+
+		start := time.Now()
 		q.shouldUpdateLocalCollections = true
-		q.synchronizeAnime(job.Diff)
+		q.publishProgress(SyncPhaseItemStarted, job.Diff.AnimeEntry.Media.ID, "anime", 0, "")
+		q.synchronizeAnime(context.Background(), job.Diff)
+		q.recordJobDuration(&q.avgAnimeDuration, time.Since(start))
 		q.checkAndUpdateLocalCollections()
 	}
 }
 
 func (q *Syncer) processMangaJobs() {
-	for job := range q.mangaJobQueue {
+	for {
+		q.waitIfPaused()
+
+		job, ok := q.mangaJobQueue.Pop(context.Background())
+		if !ok {
+			return
+		}
+
+		start := time.Now()
 		q.shouldUpdateLocalCollections = true
-		q.synchronizeManga(job.Diff)
+		q.publishProgress(SyncPhaseItemStarted, job.Diff.MangaEntry.Media.ID, "manga", 0, "")
+		q.synchronizeManga(context.Background(), job.Diff)
+		q.recordJobDuration(&q.avgMangaDuration, time.Since(start))
 		q.checkAndUpdateLocalCollections()
 	}
 }
 
+// recordJobDuration folds a just-finished job's duration into its rolling average with a 0.3 decay,
+// which publishProgress then uses to estimate ETA.
+func (q *Syncer) recordJobDuration(avg *time.Duration, d time.Duration) {
+	q.etaMu.Lock()
+	defer q.etaMu.Unlock()
+	if *avg == 0 {
+		*avg = d
+		return
+	}
+	*avg = time.Duration(float64(*avg)*0.7 + float64(d)*0.3)
+}
+
+// estimateRemaining projects how long the remaining queued anime and manga jobs will take, based
+// on the rolling average duration of recently completed jobs of each kind.
+func (q *Syncer) estimateRemaining() time.Duration {
+	q.etaMu.Lock()
+	avgAnime, avgManga := q.avgAnimeDuration, q.avgMangaDuration
+	q.etaMu.Unlock()
+
+	remainingAnime := q.progress.TotalAnime - q.progress.DoneAnime
+	remainingManga := q.progress.TotalManga - q.progress.DoneManga
+	if remainingAnime < 0 {
+		remainingAnime = 0
+	}
+	if remainingManga < 0 {
+		remainingManga = 0
+	}
+
+	return time.Duration(remainingAnime)*avgAnime + time.Duration(remainingManga)*avgManga
+}
+
+// checkAndUpdateLocalCollections runs once the anime and manga job queues have both drained. The
+// incremental collection patches applied as each job finished (see applyAnimeCollectionPatch/
+// applyMangaCollectionPatch) already keep the local collections up to date, so this no longer
+// triggers a full synchronizeCollections rebuild on every batch - only the first time (startup) or
+// once collectionsDrifted reports the remote collections changed in a way the patches didn't catch.
 func (q *Syncer) checkAndUpdateLocalCollections() {
+	q.mu.Lock()
+	// Check if we need to update the local collections, and if both queues are empty
+	if !q.shouldUpdateLocalCollections || q.animeJobQueue.Len() != 0 || q.mangaJobQueue.Len() != 0 {
+		q.mu.Unlock()
+		return
+	}
+	q.shouldUpdateLocalCollections = false
+	needsFullRebuild := !q.collectionsReady || q.collectionsDrifted()
+	q.mu.Unlock()
+
+	// synchronizeCollections is run outside the lock since it's the expensive path - holding mu
+	// here would block every applyAnimeCollectionPatch/applyMangaCollectionPatch call in the
+	// meantime for no reason.
+	if needsFullRebuild {
+		if err := q.synchronizeCollections(); err != nil {
+			q.manager.logger.Error().Err(err).Msg("sync: Failed to synchronize collections")
+		}
+	}
+	q.doneUpdatingLocalCollections <- struct{}{}
+}
+
+// scheduleCollectionsRebuild is SynchronizeNow's equivalent of checkAndUpdateLocalCollections:
+// instead of gating on the (bypassed) job queues being empty, it resets a short debounce timer so
+// a burst of concurrent single-item syncs only triggers one synchronizeCollections run.
+func (q *Syncer) scheduleCollectionsRebuild() {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
-	// Check if we need to update the local collections
-	if q.shouldUpdateLocalCollections {
-		// Check if both queues are empty
-		if len(q.animeJobQueue) == 0 && len(q.mangaJobQueue) == 0 {
-			// Update the local collections
-			err := q.synchronizeCollections()
-			if err != nil {
-				q.manager.logger.Error().Err(err).Msg("sync: Failed to synchronize collections")
-			}
-			q.shouldUpdateLocalCollections = false
-			q.doneUpdatingLocalCollections <- struct{}{}
-		}
+	if q.collectionsRebuildTimer != nil {
+		q.collectionsRebuildTimer.Stop()
 	}
+	q.collectionsRebuildTimer = time.AfterFunc(collectionsRebuildDebounce, func() {
+		if err := q.synchronizeCollections(); err != nil {
+			q.manager.logger.Error().Err(err).Msg("sync: Failed to synchronize collections")
+		}
+		select {
+		case q.doneUpdatingLocalCollections <- struct{}{}:
+		default:
+		}
+	})
 }
 
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -127,8 +477,8 @@ func (q *Syncer) synchronizeCollections() (err error) {
 	_mangaCollection := q.manager.mangaCollection.MustGet()
 
 	// Get up-to-date snapshots
-	animeSnapshots, _ := q.manager.localDb.GetAnimeSnapshots()
-	mangaSnapshots, _ := q.manager.localDb.GetMangaSnapshots()
+	animeSnapshots, _ := q.store.GetAnimeSnapshots()
+	mangaSnapshots, _ := q.store.GetMangaSnapshots()
 
 	animeSnapshotMap := make(map[int]*AnimeSnapshot)
 	for _, snapshot := range animeSnapshots {
@@ -332,14 +682,19 @@ func (q *Syncer) synchronizeCollections() (err error) {
 		}
 	}
 
-	// Save the local collections
-	err = q.manager.localDb.SaveAnimeCollection(localAnimeCollection)
+	// Save the local collections. This is guarded by collectionsMu since
+	// applyAnimeCollectionPatch/applyMangaCollectionPatch read and mutate the same
+	// manager.local*Collection fields concurrently, from the job-queue goroutines.
+	q.collectionsMu.Lock()
+	defer q.collectionsMu.Unlock()
+
+	err = q.store.SaveAnimeCollection(localAnimeCollection)
 	if err != nil {
 		return err
 	}
 	q.manager.localAnimeCollection = mo.Some(localAnimeCollection)
 
-	err = q.manager.localDb.SaveMangaCollection(localMangaCollection)
+	err = q.store.SaveMangaCollection(localMangaCollection)
 	if err != nil {
 		return err
 	}
@@ -347,19 +702,143 @@ func (q *Syncer) synchronizeCollections() (err error) {
 
 	q.manager.logger.Debug().Msg("sync: Synchronized local collections")
 
+	q.mu.Lock()
+	q.animeChecksum = computeAnimeChecksum(_animeCollection, q.trackedAnimeMap)
+	q.mangaChecksum = computeMangaChecksum(_mangaCollection, q.trackedMangaMap)
+	q.collectionsReady = true
+	q.mu.Unlock()
+
+	q.publishProgress(SyncPhaseCollectionsRebuilt, 0, "", 0, "")
+
 	return nil
 }
 
 //----------------------------------------------------------------------------------------------------------------------------------------------------
 
-func (q *Syncer) sendAnimeToFailedQueue(entry *anilist.AnimeListEntry) {
-	// TODO: Maybe send an event to the client
-	q.failedAnimeQueue.Set(entry.Media.ID, entry)
+func (q *Syncer) sendAnimeToFailedQueue(diff *AnimeDiffResult) {
+	mediaId := diff.AnimeEntry.Media.ID
+
+	q.failedMu.Lock()
+	attempts := 1
+	if existing, ok := q.failedAnimeQueue[mediaId]; ok {
+		attempts = existing.attempts + 1
+	}
+	q.failedAnimeQueue[mediaId] = &failedQueueItem[*AnimeDiffResult]{
+		diff:      diff,
+		attempts:  attempts,
+		nextRetry: time.Now().Add(retryBackoff(attempts)),
+		exhausted: attempts >= maxSyncRetries,
+	}
+	q.failedMu.Unlock()
+
+	if attempts >= maxSyncRetries {
+		q.manager.logger.Warn().Int("mediaId", mediaId).Int("attempts", attempts).Msg("sync: giving up on anime after too many failed attempts")
+	}
+
+	q.publishProgress(SyncPhaseItemFailed, mediaId, "anime", 0, "")
 }
 
-func (q *Syncer) sendMangaToFailedQueue(entry *anilist.MangaListEntry) {
+func (q *Syncer) sendMangaToFailedQueue(diff *MangaDiffResult) {
+	mediaId := diff.MangaEntry.Media.ID
 
-	q.failedMangaQueue.Set(entry.Media.ID, entry)
+	q.failedMu.Lock()
+	attempts := 1
+	if existing, ok := q.failedMangaQueue[mediaId]; ok {
+		attempts = existing.attempts + 1
+	}
+	q.failedMangaQueue[mediaId] = &failedQueueItem[*MangaDiffResult]{
+		diff:      diff,
+		attempts:  attempts,
+		nextRetry: time.Now().Add(retryBackoff(attempts)),
+		exhausted: attempts >= maxSyncRetries,
+	}
+	q.failedMu.Unlock()
+
+	if attempts >= maxSyncRetries {
+		q.manager.logger.Warn().Int("mediaId", mediaId).Int("attempts", attempts).Msg("sync: giving up on manga after too many failed attempts")
+	}
+
+	q.publishProgress(SyncPhaseItemFailed, mediaId, "manga", 0, "")
+}
+
+// retryBackoff returns an exponential backoff delay for the given attempt count, capped so an
+// item that keeps failing is still retried at a sane interval instead of effectively never.
+func retryBackoff(attempts int) time.Duration {
+	const maxBackoff = 2 * time.Hour
+	backoff := time.Duration(1<<uint(attempts)) * time.Minute
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
+
+// retryFailedLoop periodically drains the failed queues, re-queueing any entry whose backoff has
+// elapsed.
+func (q *Syncer) retryFailedLoop() {
+	defer util.HandlePanicInModuleThen("sync/retryFailedLoop", func() {})
+
+	ticker := time.NewTicker(retryCheckInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		q.drainFailedQueues()
+	}
+}
+
+func (q *Syncer) drainFailedQueues() {
+	now := time.Now()
+
+	q.failedMu.Lock()
+	var animeToRetry []*AnimeDiffResult
+	for mediaId, item := range q.failedAnimeQueue {
+		if item.exhausted || now.Before(item.nextRetry) {
+			continue
+		}
+		animeToRetry = append(animeToRetry, item.diff)
+		delete(q.failedAnimeQueue, mediaId)
+	}
+	var mangaToRetry []*MangaDiffResult
+	for mediaId, item := range q.failedMangaQueue {
+		if item.exhausted || now.Before(item.nextRetry) {
+			continue
+		}
+		mangaToRetry = append(mangaToRetry, item.diff)
+		delete(q.failedMangaQueue, mediaId)
+	}
+	q.failedMu.Unlock()
+
+	for _, diff := range animeToRetry {
+		q.publishProgress(SyncPhaseItemQueued, diff.AnimeEntry.Media.ID, "anime", 0, "")
+		q.animeJobQueue.Push(AnimeJob{Diff: diff}, animeJobPriority(diff))
+	}
+	for _, diff := range mangaToRetry {
+		q.publishProgress(SyncPhaseItemQueued, diff.MangaEntry.Media.ID, "manga", 0, "")
+		q.mangaJobQueue.Push(MangaJob{Diff: diff}, mangaJobPriority(diff))
+	}
+}
+
+// RetryFailed immediately re-queues a specific failed anime or manga, bypassing the backoff
+// schedule and the max-retries cutoff - for when a user fixes the underlying issue (e.g. frees up
+// disk space) and doesn't want to wait for the next scheduled retry.
+func (q *Syncer) RetryFailed(mediaId int) error {
+	q.failedMu.Lock()
+	if item, ok := q.failedAnimeQueue[mediaId]; ok {
+		delete(q.failedAnimeQueue, mediaId)
+		q.failedMu.Unlock()
+		q.publishProgress(SyncPhaseItemQueued, mediaId, "anime", 0, "")
+		q.animeJobQueue.Push(AnimeJob{Diff: item.diff}, animeJobPriority(item.diff))
+		return nil
+	}
+	if item, ok := q.failedMangaQueue[mediaId]; ok {
+		delete(q.failedMangaQueue, mediaId)
+		q.failedMu.Unlock()
+		q.publishProgress(SyncPhaseItemQueued, mediaId, "manga", 0, "")
+		q.mangaJobQueue.Push(MangaJob{Diff: item.diff}, mangaJobPriority(item.diff))
+		return nil
+	}
+	q.failedMu.Unlock()
+
+	return fmt.Errorf("sync: no failed item found for media %d", mediaId)
 }
 
 //----------------------------------------------------------------------------------------------------------------------------------------------------
@@ -378,6 +857,7 @@ func (q *Syncer) runDiffs(
 	defer q.mu.Unlock()
 
 	q.manager.logger.Trace().Msg("sync: Running diffs")
+	q.publishProgress(SyncPhaseDiffStart, 0, "", 0, "")
 
 	if q.manager.animeCollection.IsAbsent() {
 		q.manager.logger.Error().Msg("sync: Cannot get diffs, anime collection is absent")
@@ -389,7 +869,7 @@ func (q *Syncer) runDiffs(
 		return
 	}
 
-	if len(q.animeJobQueue) > 0 || len(q.mangaJobQueue) > 0 {
+	if q.animeJobQueue.Len() > 0 || q.mangaJobQueue.Len() > 0 {
 		q.manager.logger.Trace().Msg("sync: Skipping diffs, job queues are not empty")
 		return
 	}
@@ -434,15 +914,19 @@ func (q *Syncer) runDiffs(
 
 	wg.Wait()
 
+	q.publishProgress(SyncPhaseDiffEnd, 0, "", 0, "")
+
 	// Add the diffs to be synced asynchronously
 	go func() {
 		q.manager.logger.Trace().Int("animeJobs", len(animeDiffs)).Int("mangaJobs", len(mangaDiffs)).Msg("sync: Adding diffs to the job queues")
 
 		for _, i := range animeDiffs {
-			q.animeJobQueue <- AnimeJob{Diff: i}
+			q.publishProgress(SyncPhaseItemQueued, i.AnimeEntry.Media.ID, "anime", 0, "")
+			q.animeJobQueue.Push(AnimeJob{Diff: i}, animeJobPriority(i))
 		}
 		for _, i := range mangaDiffs {
-			q.mangaJobQueue <- MangaJob{Diff: i}
+			q.publishProgress(SyncPhaseItemQueued, i.MangaEntry.Media.ID, "manga", 0, "")
+			q.mangaJobQueue.Push(MangaJob{Diff: i}, mangaJobPriority(i))
 		}
 	}()
 
@@ -450,13 +934,110 @@ func (q *Syncer) runDiffs(
 	q.manager.logger.Trace().Msg("sync: Done running diffs")
 }
 
+// SynchronizeNow bypasses the periodic queue and immediately diffs, downloads images for, and
+// snapshots the given tracked anime or manga, then schedules a local-collection rebuild. It
+// returns once the snapshot is saved, or as soon as ctx is cancelled. The same ctx reaches the
+// metadata rate limiter, the download semaphore and the image downloads, so a cancellation also
+// abandons the snapshot attempt at whichever of those steps it reaches; a snapshot write already
+// under way is not interrupted.
+//
+// mediaId must already be tracked (i.e. present in the map runDiffs was last called with) -
+// SynchronizeNow doesn't track new media on its own.
+func (q *Syncer) SynchronizeNow(ctx context.Context, mediaId int, kind MediaKind) error {
+	if q.manager.animeCollection.IsAbsent() || q.manager.mangaCollection.IsAbsent() {
+		return fmt.Errorf("sync: cannot synchronize now, collections are not loaded yet")
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- q.synchronizeNow(ctx, mediaId, kind)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-done:
+		return err
+	}
+}
+
+// synchronizeNow does the actual diff + snapshot work for SynchronizeNow, on its own goroutine so
+// a cancelled ctx can return control to the caller without aborting the snapshot write itself.
+func (q *Syncer) synchronizeNow(ctx context.Context, mediaId int, kind MediaKind) error {
+	diff := &Diff{Logger: q.manager.logger}
+
+	switch kind {
+	case MediaKindAnime:
+		tracked, ok := q.trackedAnimeMap[mediaId]
+		if !ok {
+			return fmt.Errorf("sync: anime %d is not tracked", mediaId)
+		}
+
+		snapshots, _ := q.store.GetAnimeSnapshots()
+		snapshotMap := make(map[int]*AnimeSnapshot)
+		for _, s := range snapshots {
+			snapshotMap[s.MediaId] = s
+		}
+
+		animeDiffs := diff.GetAnimeDiffs(GetAnimeDiffOptions{
+			Collection:      q.manager.animeCollection.MustGet(),
+			LocalCollection: q.manager.localAnimeCollection,
+			LocalFiles:      q.manager.localFiles,
+			TrackedAnime:    map[int]*TrackedMedia{mediaId: tracked},
+			Snapshots:       snapshotMap,
+		})
+
+		animeDiff, ok := animeDiffs[mediaId]
+		if !ok {
+			// Nothing changed - the snapshot is already up-to-date.
+			return nil
+		}
+
+		q.publishProgress(SyncPhaseItemStarted, mediaId, "anime", 0, "")
+		q.synchronizeAnime(ctx, animeDiff)
+	case MediaKindManga:
+		tracked, ok := q.trackedMangaMap[mediaId]
+		if !ok {
+			return fmt.Errorf("sync: manga %d is not tracked", mediaId)
+		}
+
+		snapshots, _ := q.store.GetMangaSnapshots()
+		snapshotMap := make(map[int]*MangaSnapshot)
+		for _, s := range snapshots {
+			snapshotMap[s.MediaId] = s
+		}
+
+		mangaDiffs := diff.GetMangaDiffs(GetMangaDiffOptions{
+			Collection:                  q.manager.mangaCollection.MustGet(),
+			LocalCollection:             q.manager.localMangaCollection,
+			DownloadedChapterContainers: q.manager.downloadedChapterContainers,
+			TrackedManga:                map[int]*TrackedMedia{mediaId: tracked},
+			Snapshots:                   snapshotMap,
+		})
+
+		mangaDiff, ok := mangaDiffs[mediaId]
+		if !ok {
+			// Nothing changed - the snapshot is already up-to-date.
+			return nil
+		}
+
+		q.publishProgress(SyncPhaseItemStarted, mediaId, "manga", 0, "")
+		q.synchronizeManga(ctx, mangaDiff)
+	default:
+		return fmt.Errorf("sync: unknown media kind %q", kind)
+	}
+
+	q.scheduleCollectionsRebuild()
+	return nil
+}
+
 //----------------------------------------------------------------------------------------------------------------------------------------------------
 
 // synchronizeAnime creates or updates the anime snapshot in the local database.
 // The anime should be tracked.
 //   - If the anime has no local files, it will be removed entirely from the local database.
 //   - If the anime has local files, we create or update the snapshot.
-func (q *Syncer) synchronizeAnime(diff *AnimeDiffResult) {
+func (q *Syncer) synchronizeAnime(ctx context.Context, diff *AnimeDiffResult) {
 	defer util.HandlePanicInModuleThen("sync/synchronizeAnime", func() {})
 
 	entry := diff.AnimeEntry
@@ -478,13 +1059,20 @@ func (q *Syncer) synchronizeAnime(diff *AnimeDiffResult) {
 		return
 	}
 
+	metadataLimiter, downloadSemaphore := q.rateLimiters()
+
 	var animeMetadata *metadata.AnimeMetadata
 	if diff.DiffType == DiffTypeMissing || diff.DiffType == DiffTypeMetadata {
+		if err := metadataLimiter.Wait(ctx); err != nil {
+			q.sendAnimeToFailedQueue(diff)
+			return
+		}
+
 		// Get the anime metadata
 		var err error
 		animeMetadata, err = q.manager.metadataProvider.GetAnimeMetadata(metadata.AnilistPlatform, entry.Media.ID)
 		if err != nil {
-			q.sendAnimeToFailedQueue(entry)
+			q.sendAnimeToFailedQueue(diff)
 			q.manager.logger.Error().Err(err).Msgf("sync: Failed to get metadata for anime %d", entry.Media.ID)
 			return
 		}
@@ -494,9 +1082,14 @@ func (q *Syncer) synchronizeAnime(diff *AnimeDiffResult) {
 	// The snapshot is missing
 	//
 	if diff.DiffType == DiffTypeMissing {
-		bannerImage, coverImage, episodeImagePaths, ok := DownloadAnimeImages(q.manager.logger, q.manager.localAssetsDir, entry, animeMetadata)
+		if err := downloadSemaphore.Acquire(ctx); err != nil {
+			q.sendAnimeToFailedQueue(diff)
+			return
+		}
+		bannerImage, coverImage, episodeImagePaths, ok := DownloadAnimeImages(ctx, q.manager.logger, q.store.AssetsDir(), entry, animeMetadata)
+		downloadSemaphore.Release()
 		if !ok {
-			q.sendAnimeToFailedQueue(entry)
+			q.sendAnimeToFailedQueue(diff)
 			return
 		}
 
@@ -511,11 +1104,16 @@ func (q *Syncer) synchronizeAnime(diff *AnimeDiffResult) {
 		}
 
 		// Save the snapshot
-		err := q.manager.localDb.SaveAnimeSnapshot(snapshot)
+		err := q.store.SaveAnimeSnapshot(snapshot)
 		if err != nil {
-			q.sendAnimeToFailedQueue(entry)
+			q.sendAnimeToFailedQueue(diff)
 			q.manager.logger.Error().Err(err).Msgf("sync: Failed to save anime snapshot for anime %d", entry.Media.ID)
+			return
 		}
+		if !q.applyAnimeCollectionPatch(diff, snapshot) {
+			q.markCollectionsDirty()
+		}
+		q.publishProgress(SyncPhaseItemCompleted, entry.Media.ID, "anime", 0, "")
 		return
 	}
 
@@ -549,11 +1147,16 @@ func (q *Syncer) synchronizeAnime(diff *AnimeDiffResult) {
 
 		// Download the episode images if needed
 		if len(episodeImageUrlsToDownload) > 0 {
+			if err := downloadSemaphore.Acquire(ctx); err != nil {
+				q.sendAnimeToFailedQueue(diff)
+				return
+			}
 			// Download only the episode images that we need to download
-			episodeImagePaths, ok := DownloadAnimeEpisodeImages(q.manager.logger, q.manager.localAssetsDir, entry.Media.ID, episodeImageUrlsToDownload)
+			episodeImagePaths, ok := DownloadAnimeEpisodeImages(ctx, q.manager.logger, q.store.AssetsDir(), entry.Media.ID, episodeImageUrlsToDownload)
+			downloadSemaphore.Release()
 			if !ok {
 				// DownloadAnimeEpisodeImages will log the error
-				q.sendAnimeToFailedQueue(entry)
+				q.sendAnimeToFailedQueue(diff)
 				return
 			}
 			// Update the snapshot by adding the new episode images
@@ -563,11 +1166,16 @@ func (q *Syncer) synchronizeAnime(diff *AnimeDiffResult) {
 		}
 
 		// Save the snapshot
-		err := q.manager.localDb.SaveAnimeSnapshot(&snapshot)
+		err := q.store.SaveAnimeSnapshot(&snapshot)
 		if err != nil {
-			q.sendAnimeToFailedQueue(entry)
+			q.sendAnimeToFailedQueue(diff)
 			q.manager.logger.Error().Err(err).Msgf("sync: Failed to save anime snapshot for anime %d", entry.Media.ID)
+			return
+		}
+		if !q.applyAnimeCollectionPatch(diff, &snapshot) {
+			q.markCollectionsDirty()
 		}
+		q.publishProgress(SyncPhaseItemCompleted, entry.Media.ID, "anime", 0, "")
 		return
 	}
 
@@ -579,7 +1187,7 @@ func (q *Syncer) synchronizeAnime(diff *AnimeDiffResult) {
 // We know that the manga is tracked.
 //   - If the manga has no chapter containers, it will be removed entirely from the local database.
 //   - If the manga has chapter containers, we create or update the snapshot.
-func (q *Syncer) synchronizeManga(diff *MangaDiffResult) {
+func (q *Syncer) synchronizeManga(ctx context.Context, diff *MangaDiffResult) {
 	defer util.HandlePanicInModuleThen("sync/synchronizeManga", func() {})
 
 	entry := diff.MangaEntry
@@ -622,9 +1230,15 @@ func (q *Syncer) synchronizeManga(diff *MangaDiffResult) {
 	}
 
 	if diff.DiffType == DiffTypeMissing {
-		bannerImage, coverImage, ok := DownloadMangaImages(q.manager.logger, q.manager.localAssetsDir, entry)
+		_, downloadSemaphore := q.rateLimiters()
+		if err := downloadSemaphore.Acquire(ctx); err != nil {
+			q.sendMangaToFailedQueue(diff)
+			return
+		}
+		bannerImage, coverImage, ok := DownloadMangaImages(ctx, q.manager.logger, q.store.AssetsDir(), entry)
+		downloadSemaphore.Release()
 		if !ok {
-			q.sendMangaToFailedQueue(entry)
+			q.sendMangaToFailedQueue(diff)
 			return
 		}
 
@@ -638,11 +1252,16 @@ func (q *Syncer) synchronizeManga(diff *MangaDiffResult) {
 		}
 
 		// Save the snapshot
-		err := q.manager.localDb.SaveMangaSnapshot(snapshot)
+		err := q.store.SaveMangaSnapshot(snapshot)
 		if err != nil {
-			q.sendMangaToFailedQueue(entry)
+			q.sendMangaToFailedQueue(diff)
 			q.manager.logger.Error().Err(err).Msgf("sync: Failed to save manga snapshot for manga %d", entry.Media.ID)
+			return
+		}
+		if !q.applyMangaCollectionPatch(diff, snapshot) {
+			q.markCollectionsDirty()
 		}
+		q.publishProgress(SyncPhaseItemCompleted, entry.Media.ID, "manga", 0, "")
 		return
 	}
 
@@ -654,11 +1273,16 @@ func (q *Syncer) synchronizeManga(diff *MangaDiffResult) {
 		snapshot.ReferenceKey = GetMangaReferenceKey(entry.Media, eContainers)
 
 		// Save the snapshot
-		err := q.manager.localDb.SaveMangaSnapshot(&snapshot)
+		err := q.store.SaveMangaSnapshot(&snapshot)
 		if err != nil {
-			q.sendMangaToFailedQueue(entry)
+			q.sendMangaToFailedQueue(diff)
 			q.manager.logger.Error().Err(err).Msgf("sync: Failed to save manga snapshot for manga %d", entry.Media.ID)
+			return
+		}
+		if !q.applyMangaCollectionPatch(diff, &snapshot) {
+			q.markCollectionsDirty()
 		}
+		q.publishProgress(SyncPhaseItemCompleted, entry.Media.ID, "manga", 0, "")
 		return
 	}
 
@@ -0,0 +1,232 @@
+// Package quality classifies torrent release names by source/quality (BluRay, WEB-DL, WEBRip,
+// HDTV, DVD, CAM-family) and scores them, so the download pipeline can hard-drop pirated
+// cam-rips and prefer better sources when several candidates exist for the same episode.
+package quality
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ReleaseQuality classifies the source of a release, from best to worst.
+type ReleaseQuality string
+
+const (
+	ReleaseQualityBluRay ReleaseQuality = "BluRay"
+	ReleaseQualityWEBDL  ReleaseQuality = "WEB-DL"
+	ReleaseQualityWEBRip ReleaseQuality = "WEBRip"
+	ReleaseQualityHDTV   ReleaseQuality = "HDTV"
+	ReleaseQualityDVD    ReleaseQuality = "DVD"
+	// ReleaseQualityCAM covers the whole CAM/TS/TELESYNC/WORKPRINT family of theatrical
+	// bootlegs - these should always be hard-dropped, never just down-ranked.
+	ReleaseQualityCAM     ReleaseQuality = "CAM"
+	ReleaseQualityUnknown ReleaseQuality = ""
+)
+
+// qualityRank orders qualities from best (lowest index) to worst. ReleaseQualityUnknown sorts
+// last since we have no evidence it's actually good.
+var qualityRank = []ReleaseQuality{
+	ReleaseQualityBluRay,
+	ReleaseQualityWEBDL,
+	ReleaseQualityWEBRip,
+	ReleaseQualityHDTV,
+	ReleaseQualityDVD,
+	ReleaseQualityCAM,
+	ReleaseQualityUnknown,
+}
+
+// camRipTokens is the curated list of tokens (normalized: uppercased, non-word characters
+// stripped) that mark a release as a CAM-family pirated copy of a theatrical release.
+var camRipTokens = map[string]struct{}{
+	"CAMRIP":    {},
+	"CAM":       {},
+	"HDCAM":     {},
+	"TS":        {},
+	"TSRIP":     {},
+	"HDTS":      {},
+	"TELESYNC":  {},
+	"PDVD":      {},
+	"PREDVDRIP": {},
+	"TC":        {},
+	"HDTC":      {},
+	"TELECINE":  {},
+	"WP":        {},
+	"WORKPRINT": {},
+}
+
+var sourceTokens = map[string]ReleaseQuality{
+	"BLURAY": ReleaseQualityBluRay,
+	"BDRIP":  ReleaseQualityBluRay,
+	"BRRIP":  ReleaseQualityBluRay,
+	"BD":     ReleaseQualityBluRay,
+	"WEBDL":  ReleaseQualityWEBDL,
+	"WEBRIP": ReleaseQualityWEBRip,
+	"WEB":    ReleaseQualityWEBRip,
+	"HDTV":   ReleaseQualityHDTV,
+	"DVDRIP": ReleaseQualityDVD,
+	"DVD":    ReleaseQualityDVD,
+}
+
+// sourceScores anchors QualityScore to the source, before resolution bonuses are added.
+var sourceScores = map[ReleaseQuality]float64{
+	ReleaseQualityBluRay: 100,
+	ReleaseQualityWEBDL:  85,
+	ReleaseQualityWEBRip: 75,
+	ReleaseQualityHDTV:   50,
+	ReleaseQualityDVD:    40,
+	ReleaseQualityCAM:    0,
+}
+
+var resolutionScores = map[string]float64{
+	"2160P": 40,
+	"4K":    40,
+	"1080P": 30,
+	"720P":  15,
+	"480P":  5,
+}
+
+var tokenRe = regexp.MustCompile(`\W+`)
+
+// tokenize splits a release name into uppercased, non-word-delimited tokens.
+// e.g. "[Group] Show - 01 (CAM-Rip) [1080p]" -> ["GROUP", "SHOW", "01", "CAM", "RIP", "1080P"]
+func tokenize(releaseName string) []string {
+	raw := tokenRe.Split(releaseName, -1)
+	tokens := make([]string, 0, len(raw))
+	for _, t := range raw {
+		if t == "" {
+			continue
+		}
+		tokens = append(tokens, strings.ToUpper(t))
+	}
+	return tokens
+}
+
+// IsCamRip reports whether releaseName matches any of the curated CAM/TS/TELESYNC/WORKPRINT
+// family tokens. These releases should always be hard-dropped rather than merely down-ranked.
+func IsCamRip(releaseName string) bool {
+	for _, tok := range tokenize(releaseName) {
+		if _, ok := camRipTokens[tok]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Classify returns the detected ReleaseQuality and a numeric QualityScore for releaseName.
+// The score combines the source (BluRay > WEB-DL > WEBRip > HDTV > DVD > CAM) with a bonus for
+// the detected resolution, so two releases of the same source can still be ranked against each
+// other. CAM-family releases are always classified as ReleaseQualityCAM with a score of 0.
+func Classify(releaseName string) (ReleaseQuality, float64) {
+	tokens := tokenize(releaseName)
+
+	if IsCamRip(releaseName) {
+		return ReleaseQualityCAM, 0
+	}
+
+	detected := ReleaseQualityUnknown
+	for i, tok := range tokens {
+		// "WEB-DL", "Blu-Ray" and the like arrive as two tokens.
+		if i+1 < len(tokens) {
+			if q, ok := sourceTokens[tok+tokens[i+1]]; ok {
+				detected = q
+				break
+			}
+		}
+		if q, ok := sourceTokens[tok]; ok {
+			detected = q
+			break
+		}
+	}
+
+	score := sourceScores[detected]
+	for _, tok := range tokens {
+		if bonus, ok := resolutionScores[tok]; ok {
+			score += bonus
+			break
+		}
+	}
+
+	return detected, score
+}
+
+// rank returns q's position in qualityRank, defaulting to the worst rank if unrecognized.
+func rank(q ReleaseQuality) int {
+	for i, r := range qualityRank {
+		if r == q {
+			return i
+		}
+	}
+	return len(qualityRank) - 1
+}
+
+// MeetsMinimum reports whether q is at least as good as min (BluRay is "better than" WEBRip, etc).
+func MeetsMinimum(q ReleaseQuality, min ReleaseQuality) bool {
+	if min == ReleaseQualityUnknown {
+		return true
+	}
+	return rank(q) <= rank(min)
+}
+
+// FilterOptions controls FilterAndRank's pruning and ordering behavior.
+type FilterOptions struct {
+	// ExcludeCamRips hard-drops any candidate classified as ReleaseQualityCAM.
+	ExcludeCamRips bool
+	// MinQuality drops any candidate worse than this quality. Leave as ReleaseQualityUnknown
+	// to disable the floor.
+	MinQuality ReleaseQuality
+	// Preferred is an ordered, most-preferred-first list of qualities. Candidates matching an
+	// earlier entry sort before candidates matching a later one; candidates that tie on
+	// Preferred fall back to QualityScore.
+	Preferred []ReleaseQuality
+}
+
+// FilterAndRank prunes candidates that fail opts (cam-rips, below MinQuality) and sorts the
+// rest by opts.Preferred, then by descending QualityScore. releaseName extracts the raw
+// release-name string from a candidate, so this works for any torrent/candidate type without
+// this package needing to know its shape.
+func FilterAndRank[T any](candidates []T, releaseName func(T) string, opts FilterOptions) []T {
+	type scored struct {
+		candidate T
+		quality   ReleaseQuality
+		score     float64
+	}
+
+	scoredCandidates := make([]scored, 0, len(candidates))
+	for _, c := range candidates {
+		name := releaseName(c)
+		q, score := Classify(name)
+
+		if opts.ExcludeCamRips && q == ReleaseQualityCAM {
+			continue
+		}
+		if opts.MinQuality != ReleaseQualityUnknown && !MeetsMinimum(q, opts.MinQuality) {
+			continue
+		}
+
+		scoredCandidates = append(scoredCandidates, scored{candidate: c, quality: q, score: score})
+	}
+
+	preferredRank := func(q ReleaseQuality) int {
+		for i, p := range opts.Preferred {
+			if p == q {
+				return i
+			}
+		}
+		return len(opts.Preferred)
+	}
+
+	sort.SliceStable(scoredCandidates, func(i, j int) bool {
+		ri, rj := preferredRank(scoredCandidates[i].quality), preferredRank(scoredCandidates[j].quality)
+		if ri != rj {
+			return ri < rj
+		}
+		return scoredCandidates[i].score > scoredCandidates[j].score
+	})
+
+	ret := make([]T, len(scoredCandidates))
+	for i, sc := range scoredCandidates {
+		ret[i] = sc.candidate
+	}
+	return ret
+}
@@ -0,0 +1,101 @@
+// Package downloadclient abstracts over the torrent client backends the auto-downloader and
+// torrent-streaming subsystems hand magnets/torrents off to, so users can pick qBittorrent,
+// Aria2, or the built-in native client from settings instead of being locked into one.
+package downloadclient
+
+import (
+	"errors"
+)
+
+// ClientType identifies a download-client backend, as stored in models.TorrentSettings.ClientType.
+type ClientType string
+
+const (
+	ClientTypeNative      ClientType = "native"
+	ClientTypeQbittorrent ClientType = "qbittorrent"
+	ClientTypeAria2       ClientType = "aria2"
+)
+
+var (
+	ErrNotFound      = errors.New("downloadclient: torrent not found")
+	ErrUnauthorized  = errors.New("downloadclient: authentication failed")
+	ErrUnreachable   = errors.New("downloadclient: client unreachable")
+	ErrUnsupportedOp = errors.New("downloadclient: operation not supported by this backend")
+)
+
+// TorrentState is a backend-agnostic view of a torrent's lifecycle state.
+type TorrentState string
+
+const (
+	TorrentStateDownloading TorrentState = "downloading"
+	TorrentStateSeeding     TorrentState = "seeding"
+	TorrentStatePaused      TorrentState = "paused"
+	TorrentStateQueued      TorrentState = "queued"
+	TorrentStateError       TorrentState = "error"
+	TorrentStateUnknown     TorrentState = "unknown"
+)
+
+// Torrent is the backend-agnostic representation of a single torrent, shared by every
+// DownloadClient implementation.
+type Torrent struct {
+	Hash        string       `json:"hash"`
+	Name        string       `json:"name"`
+	State       TorrentState `json:"state"`
+	Progress    float64      `json:"progress"` // 0-1
+	SavePath    string       `json:"savePath"`
+	ContentPath string       `json:"contentPath"`
+	Seeds       int          `json:"seeds"`
+	UploadSpeed int64        `json:"uploadSpeed"`   // bytes/s
+	DownSpeed   int64        `json:"downloadSpeed"` // bytes/s
+	Labels      []string     `json:"labels"`
+}
+
+// File is a single file within a torrent's content.
+type File struct {
+	Index    int     `json:"index"`
+	Name     string  `json:"name"`
+	Size     int64   `json:"size"`
+	Progress float64 `json:"progress"` // 0-1
+}
+
+// AddOptions customizes how a torrent/magnet is added to the client.
+type AddOptions struct {
+	SavePath string
+	Category string
+	Paused   bool
+}
+
+// Capabilities describes what a backend can and can't do, so callers (and the UI) can adapt
+// instead of calling an operation blindly and handling ErrUnsupportedOp every time.
+type Capabilities struct {
+	ClientType       ClientType `json:"clientType"`
+	Version          string     `json:"version"`
+	SupportsLabels   bool       `json:"supportsLabels"`
+	SupportsPause    bool       `json:"supportsPause"`
+	SupportsFiles    bool       `json:"supportsFiles"`
+	SupportsSavePath bool       `json:"supportsSavePath"`
+}
+
+// DownloadClient is implemented by every backend (native, qBittorrent, Aria2, ...). The
+// auto-downloader and torrent-streaming repository depend on this interface rather than a
+// concrete client so the backend can be swapped from settings without touching either of them.
+type DownloadClient interface {
+	// Add adds a magnet link or .torrent file/URL to the client and returns the hash the other
+	// methods take: the info hash for qBittorrent, the download's GID for Aria2.
+	Add(magnetOrTorrent string, opts AddOptions) (hash string, err error)
+	// Remove removes a torrent by hash, optionally deleting its downloaded data.
+	Remove(hash string, deleteData bool) error
+	// List returns every torrent currently known to the client.
+	List() ([]*Torrent, error)
+	// Get returns a single torrent by hash, or ErrNotFound.
+	Get(hash string) (*Torrent, error)
+	// Pause/Resume control a torrent's transfer state.
+	Pause(hash string) error
+	Resume(hash string) error
+	// SetLabels replaces a torrent's labels/category.
+	SetLabels(hash string, labels []string) error
+	// Files lists the files contained in a torrent.
+	Files(hash string) ([]*File, error)
+	// Capabilities describes what this backend instance supports.
+	Capabilities() Capabilities
+}
@@ -0,0 +1,38 @@
+package downloadclient
+
+import (
+	"fmt"
+	"github.com/rs/zerolog"
+	"seanime/internal/database/models"
+)
+
+// NewFromSettings builds a DownloadClient from the user's Torrent settings. native is the
+// caller's existing native-client implementation, used as-is when settings.ClientType is empty
+// or ClientTypeNative - this is what lets the auto-downloader and torrent-streaming repository
+// depend on the interface without caring which backend actually ends up behind it.
+func NewFromSettings(settings *models.TorrentSettings, native DownloadClient, logger *zerolog.Logger) (DownloadClient, error) {
+	if settings == nil {
+		return native, nil
+	}
+
+	switch ClientType(settings.ClientType) {
+	case "", ClientTypeNative:
+		return native, nil
+	case ClientTypeQbittorrent:
+		return NewQbittorrentClient(QbittorrentConfig{
+			Host:     settings.Host,
+			Port:     settings.Port,
+			Username: settings.Username,
+			Password: settings.Password,
+		}, logger)
+	case ClientTypeAria2:
+		return NewAria2Client(Aria2Config{
+			Host:     settings.Host,
+			Port:     settings.Port,
+			Secret:   settings.Password, // aria2 has no username, only --rpc-secret
+			SavePath: settings.SavePath,
+		}, logger)
+	default:
+		return nil, fmt.Errorf("downloadclient: unknown client type %q", settings.ClientType)
+	}
+}
@@ -0,0 +1,441 @@
+package downloadclient
+
+import (
+	"errors"
+	"fmt"
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// QbittorrentConfig configures a qBittorrent Web API (v2) client.
+type QbittorrentConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+}
+
+// qbittorrent talks to qBittorrent's Web API v2 (https://<host>:<port>/api/v2/...). Authentication
+// is cookie-based (SID), so the client logs in once and re-logs-in transparently whenever a
+// request comes back 403 - the cookie can expire or be invalidated from outside (e.g. the user
+// logging in from the WebUI).
+type qbittorrent struct {
+	cfg     QbittorrentConfig
+	baseURL string
+	client  *http.Client
+	logger  *zerolog.Logger
+
+	mu         sync.Mutex
+	sid        string
+	apiVersion string // e.g. "2.8.3", from GET /api/v2/app/webapiVersion
+}
+
+// NewQbittorrentClient logs into qBittorrent and returns a ready-to-use DownloadClient. The
+// returned client's Capabilities().Version reflects the probed Web API version, which is used
+// internally only to choose between the pause/resume and stop/start endpoints (see
+// isV5OrNewer). Labels are set as the category on every version; tags are not used.
+func NewQbittorrentClient(cfg QbittorrentConfig, logger *zerolog.Logger) (DownloadClient, error) {
+	qb := &qbittorrent{
+		cfg:     cfg,
+		baseURL: fmt.Sprintf("http://%s:%d", cfg.Host, cfg.Port),
+		client:  &http.Client{Timeout: 15 * time.Second},
+		logger:  logger,
+	}
+
+	if err := qb.login(); err != nil {
+		return nil, err
+	}
+
+	if v, err := qb.get("/api/v2/app/webapiVersion"); err == nil {
+		qb.apiVersion = strings.TrimSpace(string(v))
+	} else {
+		logger.Warn().Err(err).Msg("qbittorrent: failed to probe web API version")
+	}
+
+	return qb, nil
+}
+
+func (qb *qbittorrent) login() error {
+	form := url.Values{}
+	form.Set("username", qb.cfg.Username)
+	form.Set("password", qb.cfg.Password)
+
+	req, err := http.NewRequest(http.MethodPost, qb.baseURL+"/api/v2/auth/login", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Referer", qb.baseURL)
+
+	resp, err := qb.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUnreachable, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || strings.TrimSpace(string(body)) != "Ok." {
+		return ErrUnauthorized
+	}
+
+	for _, c := range resp.Cookies() {
+		if c.Name == "SID" {
+			qb.mu.Lock()
+			qb.sid = c.Value
+			qb.mu.Unlock()
+			return nil
+		}
+	}
+
+	return ErrUnauthorized
+}
+
+// do performs an authenticated request, transparently re-logging-in once if the session has
+// expired.
+func (qb *qbittorrent) do(method, path string, form url.Values) ([]byte, error) {
+	body, err := qb.doOnce(method, path, form)
+	if errors.Is(err, ErrUnauthorized) {
+		if loginErr := qb.login(); loginErr != nil {
+			return nil, loginErr
+		}
+		return qb.doOnce(method, path, form)
+	}
+	return body, err
+}
+
+func (qb *qbittorrent) doOnce(method, path string, form url.Values) ([]byte, error) {
+	var reqBody io.Reader
+	reqURL := qb.baseURL + path
+	if method == http.MethodGet {
+		if form != nil {
+			reqURL += "?" + form.Encode()
+		}
+	} else if form != nil {
+		reqBody = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequest(method, reqURL, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if method != http.MethodGet {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	req.Header.Set("Referer", qb.baseURL)
+
+	qb.mu.Lock()
+	sid := qb.sid
+	qb.mu.Unlock()
+	if sid != "" {
+		req.AddCookie(&http.Cookie{Name: "SID", Value: sid})
+	}
+
+	resp, err := qb.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnreachable, err.Error())
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusForbidden {
+		return nil, ErrUnauthorized
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("qbittorrent: %s %s returned %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return respBody, nil
+}
+
+func (qb *qbittorrent) get(path string) ([]byte, error) {
+	return qb.do(http.MethodGet, path, nil)
+}
+
+func (qb *qbittorrent) Add(magnetOrTorrent string, opts AddOptions) (string, error) {
+	// Snapshotted before the add so a non-magnet input (plain .torrent file/URL) can still be
+	// resolved afterwards by diffing against it - extractMagnetHash has nothing to go on for those.
+	before, err := qb.hashSet()
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("urls", magnetOrTorrent)
+	if opts.SavePath != "" {
+		form.Set("savepath", opts.SavePath)
+	}
+	if opts.Category != "" {
+		form.Set("category", opts.Category)
+	}
+	if opts.Paused {
+		form.Set("paused", "true")
+	}
+
+	if _, err := qb.do(http.MethodPost, "/api/v2/torrents/add", form); err != nil {
+		return "", err
+	}
+
+	// qBittorrent's /add endpoint doesn't return the hash, so we resolve it by looking up the
+	// newly-added torrent among /torrents/info, by the magnet's info hash or, for a .torrent
+	// file/URL, as the one hash missing from before.
+	return qb.resolveAddedHash(magnetOrTorrent, before)
+}
+
+// hashSet returns the set of hashes qBittorrent currently knows about, for resolveAddedHash to
+// diff a non-magnet Add against.
+func (qb *qbittorrent) hashSet() (map[string]struct{}, error) {
+	torrents, err := qb.List()
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]struct{}, len(torrents))
+	for _, t := range torrents {
+		set[strings.ToLower(t.Hash)] = struct{}{}
+	}
+	return set, nil
+}
+
+// resolveAddedHash finds the hash of the torrent magnetOrTorrent just added. For a magnet link,
+// the hash is embedded in the URI itself, so it's matched directly. A plain .torrent file/URL has
+// no hash until qBittorrent parses it, so that case is instead resolved by diffing the current
+// torrent list against before (the set captured right before the add) and returning the one new
+// hash that appeared.
+func (qb *qbittorrent) resolveAddedHash(magnetOrTorrent string, before map[string]struct{}) (string, error) {
+	wantHash, isMagnet := extractMagnetHash(magnetOrTorrent)
+
+	const maxAttempts = 10
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		torrents, err := qb.List()
+		if err != nil {
+			return "", err
+		}
+
+		if isMagnet {
+			for _, t := range torrents {
+				if strings.EqualFold(t.Hash, wantHash) {
+					return t.Hash, nil
+				}
+			}
+		} else {
+			var added []string
+			for _, t := range torrents {
+				if _, seen := before[strings.ToLower(t.Hash)]; !seen {
+					added = append(added, t.Hash)
+				}
+			}
+			// Only resolve once exactly one new torrent has shown up - with more than one, a
+			// concurrent Add from elsewhere makes the diff ambiguous, so keep polling in case it
+			// was just the other add's torrent that hadn't settled down yet.
+			if len(added) == 1 {
+				return added[0], nil
+			}
+		}
+
+		time.Sleep(500 * time.Millisecond)
+	}
+	return "", fmt.Errorf("qbittorrent: could not resolve hash for added torrent")
+}
+
+func (qb *qbittorrent) Remove(hash string, deleteData bool) error {
+	form := url.Values{}
+	form.Set("hashes", hash)
+	form.Set("deleteFiles", strconv.FormatBool(deleteData))
+	_, err := qb.do(http.MethodPost, "/api/v2/torrents/delete", form)
+	return err
+}
+
+func (qb *qbittorrent) List() ([]*Torrent, error) {
+	body, err := qb.get("/api/v2/torrents/info")
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []qbittorrentTorrentInfo
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	torrents := make([]*Torrent, 0, len(raw))
+	for _, t := range raw {
+		torrents = append(torrents, t.toTorrent())
+	}
+	return torrents, nil
+}
+
+func (qb *qbittorrent) Get(hash string) (*Torrent, error) {
+	torrents, err := qb.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range torrents {
+		if strings.EqualFold(t.Hash, hash) {
+			return t, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (qb *qbittorrent) Pause(hash string) error {
+	form := url.Values{"hashes": {hash}}
+	_, err := qb.do(http.MethodPost, qb.pauseEndpoint(), form)
+	return err
+}
+
+func (qb *qbittorrent) Resume(hash string) error {
+	form := url.Values{"hashes": {hash}}
+	_, err := qb.do(http.MethodPost, qb.resumeEndpoint(), form)
+	return err
+}
+
+// pauseEndpoint/resumeEndpoint account for qBittorrent 5.0 renaming /torrents/pause and
+// /torrents/resume to /torrents/stop and /torrents/start. Versions older than "5" keep using the
+// original names.
+func (qb *qbittorrent) pauseEndpoint() string {
+	if qb.isV5OrNewer() {
+		return "/api/v2/torrents/stop"
+	}
+	return "/api/v2/torrents/pause"
+}
+
+func (qb *qbittorrent) resumeEndpoint() string {
+	if qb.isV5OrNewer() {
+		return "/api/v2/torrents/start"
+	}
+	return "/api/v2/torrents/resume"
+}
+
+func (qb *qbittorrent) isV5OrNewer() bool {
+	return strings.HasPrefix(qb.apiVersion, "3.") // Web API v3.x ships with qBittorrent 5.0+
+}
+
+func (qb *qbittorrent) SetLabels(hash string, labels []string) error {
+	category := ""
+	if len(labels) > 0 {
+		category = labels[0]
+	}
+
+	form := url.Values{}
+	form.Set("hashes", hash)
+	form.Set("category", category)
+	_, err := qb.do(http.MethodPost, "/api/v2/torrents/setCategory", form)
+	return err
+}
+
+func (qb *qbittorrent) Files(hash string) ([]*File, error) {
+	form := url.Values{"hash": {hash}}
+	body, err := qb.do(http.MethodGet, "/api/v2/torrents/files", form)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []qbittorrentFileInfo
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	files := make([]*File, 0, len(raw))
+	for i, f := range raw {
+		files = append(files, &File{
+			Index:    i,
+			Name:     f.Name,
+			Size:     f.Size,
+			Progress: f.Progress,
+		})
+	}
+	return files, nil
+}
+
+func (qb *qbittorrent) Capabilities() Capabilities {
+	return Capabilities{
+		ClientType:       ClientTypeQbittorrent,
+		Version:          qb.apiVersion,
+		SupportsLabels:   true,
+		SupportsPause:    true,
+		SupportsFiles:    true,
+		SupportsSavePath: true,
+	}
+}
+
+type qbittorrentTorrentInfo struct {
+	Hash        string  `json:"hash"`
+	Name        string  `json:"name"`
+	State       string  `json:"state"`
+	Progress    float64 `json:"progress"`
+	SavePath    string  `json:"save_path"`
+	ContentPath string  `json:"content_path"`
+	NumSeeds    int     `json:"num_seeds"`
+	UpSpeed     int64   `json:"upspeed"`
+	DlSpeed     int64   `json:"dlspeed"`
+	Category    string  `json:"category"`
+}
+
+func (t qbittorrentTorrentInfo) toTorrent() *Torrent {
+	labels := make([]string, 0, 1)
+	if t.Category != "" {
+		labels = append(labels, t.Category)
+	}
+
+	return &Torrent{
+		Hash:        t.Hash,
+		Name:        t.Name,
+		State:       qbittorrentState(t.State),
+		Progress:    t.Progress,
+		SavePath:    t.SavePath,
+		ContentPath: t.ContentPath,
+		Seeds:       t.NumSeeds,
+		UploadSpeed: t.UpSpeed,
+		DownSpeed:   t.DlSpeed,
+		Labels:      labels,
+	}
+}
+
+// qbittorrentState maps qBittorrent's many granular states (https://github.com/qbittorrent/qBittorrent/wiki/WebUI-API)
+// down to our small, backend-agnostic set.
+func qbittorrentState(state string) TorrentState {
+	switch state {
+	case "downloading", "metaDL", "forcedDL", "allocating":
+		return TorrentStateDownloading
+	case "uploading", "stalledUP", "forcedUP", "queuedUP", "checkingUP":
+		return TorrentStateSeeding
+	case "pausedDL", "pausedUP", "stoppedDL", "stoppedUP":
+		return TorrentStatePaused
+	case "queuedDL", "checkingDL", "checkingResumeData", "moving":
+		return TorrentStateQueued
+	case "error", "missingFiles", "unknown":
+		return TorrentStateError
+	default:
+		return TorrentStateUnknown
+	}
+}
+
+type qbittorrentFileInfo struct {
+	Name     string  `json:"name"`
+	Size     int64   `json:"size"`
+	Progress float64 `json:"progress"`
+}
+
+// extractMagnetHash pulls the info-hash (btih) out of a magnet URI. It returns false for plain
+// .torrent file paths/URLs, since those have no hash until qBittorrent parses them.
+func extractMagnetHash(magnetOrTorrent string) (string, bool) {
+	const prefix = "urn:btih:"
+	idx := strings.Index(strings.ToLower(magnetOrTorrent), prefix)
+	if idx == -1 {
+		return "", false
+	}
+	rest := magnetOrTorrent[idx+len(prefix):]
+	if amp := strings.IndexByte(rest, '&'); amp != -1 {
+		rest = rest[:amp]
+	}
+	return rest, len(rest) > 0
+}
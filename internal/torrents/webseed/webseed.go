@@ -0,0 +1,70 @@
+// Package webseed validates HTTP mirrors as BEP19 web seeds (GetRight-style) for the
+// torrent-streaming subsystem. The settings handlers save the mirror list as the torrent-streaming
+// settings' WebseedURLs, but nothing registers those URLs on a torrent when a stream starts; this
+// package only covers the "is this mirror usable" check, used by the settings-UI test endpoint.
+package webseed
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// testTimeout bounds how long a single URL's HEAD request is allowed to take, so a dead or
+// slow-to-respond mirror can't stall the test endpoint.
+const testTimeout = 5 * time.Second
+
+// Status is the per-URL result of TestURLs.
+type Status struct {
+	URL           string `json:"url"`
+	OK            bool   `json:"ok"`
+	StatusCode    int    `json:"statusCode"`
+	AcceptsRanges bool   `json:"acceptsRanges"`
+	Error         string `json:"error,omitempty"`
+}
+
+// TestURLs HEADs each url and reports whether it looks usable as a BEP19 web seed. A mirror is
+// only OK when it responds 2xx and advertises "Accept-Ranges: bytes" - web seeds are fetched
+// piece-by-piece via ranged GETs, so a server that can't serve ranges can't actually be used as
+// one, even if the URL itself is reachable.
+func TestURLs(urls []string) []Status {
+	statuses := make([]Status, 0, len(urls))
+	for _, url := range urls {
+		statuses = append(statuses, testURL(url))
+	}
+	return statuses
+}
+
+func testURL(url string) Status {
+	status := Status{URL: url}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	defer res.Body.Close()
+
+	status.StatusCode = res.StatusCode
+	status.AcceptsRanges = res.Header.Get("Accept-Ranges") == "bytes"
+	status.OK = res.StatusCode >= 200 && res.StatusCode < 300 && status.AcceptsRanges
+
+	if !status.OK && status.Error == "" {
+		if status.StatusCode < 200 || status.StatusCode >= 300 {
+			status.Error = "unexpected status code"
+		} else {
+			status.Error = "mirror does not advertise Accept-Ranges: bytes"
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,255 @@
+// Package httpcache provides a file-backed http.RoundTripper cache used by the MangaDex manga
+// provider to avoid re-fetching the same responses on every run. The other manga providers and
+// the image downloader do not go through it.
+package httpcache
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"github.com/goccy/go-json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// Transport wraps an http.RoundTripper and caches responses on disk under
+	// os.UserCacheDir()/seanime/http/<sha1(url)>.
+	Transport struct {
+		Next http.RoundTripper
+		// DefaultTTL is used when the response carries no Cache-Control/Expires header.
+		DefaultTTL time.Duration
+
+		enabled  atomic.Bool
+		cacheDir string
+		mu       sync.Mutex
+	}
+
+	cacheEntry struct {
+		StatusCode int                 `json:"statusCode"`
+		Header     map[string][]string `json:"header"`
+		Body       []byte              `json:"body"`
+		ExpiresAt  int64               `json:"expiresAt"` // unix seconds, 0 = no expiry info
+	}
+)
+
+// New creates a new cache Transport. If next is nil, http.DefaultTransport is used.
+// The cache starts enabled.
+func New(next http.RoundTripper, defaultTTL time.Duration) (*Transport, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	baseDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Transport{
+		Next:       next,
+		DefaultTTL: defaultTTL,
+		cacheDir:   filepath.Join(baseDir, "seanime", "http"),
+	}
+	t.enabled.Store(true)
+
+	if err := os.MkdirAll(t.cacheDir, 0755); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// EnableCache turns on response caching.
+func (t *Transport) EnableCache() {
+	t.enabled.Store(true)
+}
+
+// DisableCache turns off response caching. Requests made while it is off always hit the
+// network, but existing cache entries on disk are left untouched.
+func (t *Transport) DisableCache() {
+	t.enabled.Store(false)
+}
+
+// IsCacheEnabled reports whether caching is currently turned on.
+func (t *Transport) IsCacheEnabled() bool {
+	return t.enabled.Load()
+}
+
+// Purge deletes every cached entry whose request URL, as sent (not canonicalized), starts with prefix.
+// Passing an empty prefix purges everything.
+func (t *Transport) Purge(prefix string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entries, err := os.ReadDir(t.cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(t.cacheDir, entry.Name())
+		if prefix == "" {
+			_ = os.Remove(path)
+			continue
+		}
+		meta, err := readMetaURL(path)
+		if err == nil && strings.HasPrefix(meta, prefix) {
+			_ = os.Remove(path)
+		}
+	}
+
+	return nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || !t.enabled.Load() {
+		return t.Next.RoundTrip(req)
+	}
+
+	key := keyFor(req.URL)
+	path := filepath.Join(t.cacheDir, key)
+
+	if entry, ok := t.readEntry(path); ok {
+		return entry.toResponse(req), nil
+	}
+
+	resp, err := t.Next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 400 {
+		t.writeEntry(path, req.URL.String(), resp)
+	}
+
+	return resp, nil
+}
+
+func (t *Transport) readEntry(path string) (*cacheEntry, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+
+	if entry.ExpiresAt > 0 && time.Now().Unix() > entry.ExpiresAt {
+		_ = os.Remove(path)
+		return nil, false
+	}
+
+	return &entry, true
+}
+
+func (t *Transport) writeEntry(path string, rawURL string, resp *http.Response) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	expiresAt := cacheControlExpiry(resp.Header)
+	if expiresAt == 0 && t.DefaultTTL > 0 {
+		expiresAt = time.Now().Add(t.DefaultTTL).Unix()
+	}
+
+	entry := cacheEntry{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+		ExpiresAt:  expiresAt,
+	}
+	// Stash the source URL so Purge(prefix) can match against it without re-parsing the key.
+	entry.Header["X-Httpcache-Url"] = []string{rawURL}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	_ = os.WriteFile(path, data, 0644)
+}
+
+func (e *cacheEntry) toResponse(req *http.Request) *http.Response {
+	header := http.Header(e.Header)
+	return &http.Response{
+		StatusCode:    e.StatusCode,
+		Status:        strconv.Itoa(e.StatusCode),
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+func readMetaURL(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return "", err
+	}
+	if urls, ok := entry.Header["X-Httpcache-Url"]; ok && len(urls) > 0 {
+		return urls[0], nil
+	}
+	return "", nil
+}
+
+// keyFor canonicalizes the URL (scheme, host, path and sorted query) and returns its sha1 hex digest.
+func keyFor(u *url.URL) string {
+	canonical := canonicalizeURL(u)
+	sum := sha1.Sum([]byte(canonical))
+	return hex.EncodeToString(sum[:])
+}
+
+func canonicalizeURL(u *url.URL) string {
+	q := u.Query()
+	return fmt.Sprintf("%s://%s%s?%s", strings.ToLower(u.Scheme), strings.ToLower(u.Host), u.Path, q.Encode())
+}
+
+// cacheControlExpiry returns a unix timestamp derived from Cache-Control: max-age or Expires, 0 if absent.
+func cacheControlExpiry(header http.Header) int64 {
+	if cc := header.Get("Cache-Control"); cc != "" {
+		for _, directive := range strings.Split(cc, ",") {
+			directive = strings.TrimSpace(directive)
+			if strings.HasPrefix(directive, "max-age=") {
+				if secs, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil {
+					return time.Now().Add(time.Duration(secs) * time.Second).Unix()
+				}
+			}
+			if directive == "no-store" || directive == "no-cache" {
+				return time.Now().Unix() - 1 // already expired, effectively uncached
+			}
+		}
+	}
+
+	if exp := header.Get("Expires"); exp != "" {
+		if t, err := http.ParseTime(exp); err == nil {
+			return t.Unix()
+		}
+	}
+
+	return 0
+}